@@ -24,31 +24,59 @@ type joinTable struct {
 	attrs     []attr.Attributer
 }
 
+type polymorphic struct {
+	typeField string
+	typeCol   string
+	typeValue string
+}
+
 type Association struct {
 	factory         *Factory
 	fieldName       string
-	foreignKey      string
-	foreignField    string
+	foreignKeys     []string
+	foreignFields   []string
 	associatedField string
-	referField      string
-	referCol        string
+	referFields     []string
+	referCols       []string
 	joinTable       *joinTable
+	polymorphic     *polymorphic
 	num             int32
 	assType         AssociationType
+	self            bool
+	depth           int32
+	infer           bool
+	inverseField    string
+}
+
+// SelfAssociation create an association which refers to the building factory itself,
+// the referred factory is resolved lazily when the object is built
+func SelfAssociation() *Association {
+	return &Association{
+		self: true,
+	}
 }
 
 func (as *Association) clone() *Association {
+	var factory *Factory
+	if as.factory != nil {
+		factory = as.factory.Clone()
+	}
 	return &Association{
-		factory:         as.factory.Clone(),
+		factory:         factory,
 		fieldName:       as.fieldName,
-		foreignKey:      as.foreignKey,
-		referField:      as.referField,
-		referCol:        as.referCol,
-		foreignField:    as.foreignField,
+		foreignKeys:     as.foreignKeys,
+		referFields:     as.referFields,
+		referCols:       as.referCols,
+		foreignFields:   as.foreignFields,
 		joinTable:       as.joinTable,
+		polymorphic:     as.polymorphic,
 		associatedField: as.associatedField,
 		num:             as.num,
 		assType:         as.assType,
+		self:            as.self,
+		depth:           as.depth,
+		infer:           as.infer,
+		inverseField:    as.inverseField,
 	}
 }
 
@@ -58,9 +86,22 @@ func (as *Association) AssociatedField(asField string) *Association {
 	return cloned
 }
 
+// Inverse setup the field of associated object which refers back to the current object (e.g Task.Project for Project.Tasks),
+// the field can be a pointer, struct or slice, for ManyToMany it is used as the associated field if AssociatedField is empty
+func (as *Association) Inverse(inverseField string) *Association {
+	cloned := as.clone()
+	cloned.inverseField = inverseField
+	return cloned
+}
+
 func (as *Association) ReferColumn(referCol string) *Association {
+	return as.ReferColumns(referCol)
+}
+
+// ReferColumns setup the columns of join table which correspond to the composite refer fields
+func (as *Association) ReferColumns(referCols ...string) *Association {
 	cloned := as.clone()
-	cloned.referCol = referCol
+	cloned.referCols = referCols
 	return cloned
 }
 
@@ -68,14 +109,31 @@ func (as *Association) JoinTable(joinTableName string, attrs ...attr.Attributer)
 	cloned := as.clone()
 	cloned.joinTable = &joinTable{
 		tableName: joinTableName,
-		attrs:     attrs,
+		attrs:     attr.Expand(attrs...),
+	}
+	return cloned
+}
+
+// Polymorphic mark the association as polymorphic, the discriminator field(typeField) and column(typeCol)
+// of the object which holds the foreign key will be assigned by typeValue
+func (as *Association) Polymorphic(typeField, typeCol, typeValue string) *Association {
+	cloned := as.clone()
+	cloned.polymorphic = &polymorphic{
+		typeField: typeField,
+		typeCol:   typeCol,
+		typeValue: typeValue,
 	}
 	return cloned
 }
 
 func (as *Association) ReferField(referField string) *Association {
+	return as.ReferFields(referField)
+}
+
+// ReferFields setup the fields referred by the composite foreign key
+func (as *Association) ReferFields(referFields ...string) *Association {
 	cloned := as.clone()
-	cloned.referField = referField
+	cloned.referFields = referFields
 	return cloned
 }
 
@@ -86,14 +144,24 @@ func (as *Association) FieldName(fieldName string) *Association {
 }
 
 func (as *Association) ForeignField(foreignField string) *Association {
+	return as.ForeignFields(foreignField)
+}
+
+// ForeignFields setup the fields which are assigned by the composite refer fields
+func (as *Association) ForeignFields(foreignFields ...string) *Association {
 	cloned := as.clone()
-	cloned.foreignField = foreignField
+	cloned.foreignFields = foreignFields
 	return cloned
 }
 
 func (as *Association) ForeignKey(foreignKey string) *Association {
+	return as.ForeignKeys(foreignKey)
+}
+
+// ForeignKeys setup the columns of the composite foreign key
+func (as *Association) ForeignKeys(foreignKeys ...string) *Association {
 	cloned := as.clone()
-	cloned.foreignKey = foreignKey
+	cloned.foreignKeys = foreignKeys
 	return cloned
 }
 
@@ -103,100 +171,246 @@ func (as *Association) Num(num int32) *Association {
 	return cloned
 }
 
-func (as *Association) buildForeignFieldValue(val reflect.Value) (*foreignFieldValue, error) {
-	if as.referField != "" {
-		fieldVal := reflectutil.GetFieldValue(val.Interface(), as.referField)
+func (as *Association) buildForeignFieldValues(val reflect.Value, insert bool) ([]*foreignFieldValue, error) {
+	if len(as.referFields) == 0 {
+		return nil, nil
+	}
+	if err := validateKeys(as.fieldName, "refer fields", as.referFields, "foreign fields", as.foreignFields); err != nil {
+		return nil, err
+	}
+	if insert {
+		if err := validateKeys(as.fieldName, "refer fields", as.referFields, "foreign keys", as.foreignKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	fvs := make([]*foreignFieldValue, len(as.referFields))
+	for i, referField := range as.referFields {
+		fieldVal := reflectutil.GetFieldValue(val.Interface(), referField)
 		if fieldVal == nil {
-			return nil, fmt.Errorf("association: has many association referField(%s) not found", as.referField)
+			return nil, fmt.Errorf("association: has many association referField(%s) not found", referField)
 		}
-		return &foreignFieldValue{
+		fv := &foreignFieldValue{
 			val:       fieldVal,
-			fieldName: as.foreignField,
-			colName:   as.foreignKey,
-		}, nil
+			fieldName: as.foreignFields[i],
+		}
+		if insert {
+			fv.colName = as.foreignKeys[i]
+		}
+		fvs[i] = fv
+	}
+	return fvs, nil
+}
+
+func validateKeys(fieldName, aName string, a []string, bName string, b []string) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("association: field(%s), the number of %s(%d) and %s(%d) mismatch", fieldName, aName, len(a), bName, len(b))
+	}
+	for i := range a {
+		if a[i] == "" || b[i] == "" {
+			return fmt.Errorf("association: field(%s), %s or %s is empty", fieldName, aName, bName)
+		}
+	}
+	return nil
+}
+
+// validateKeyTypes check the refer fields and foreign fields are of compatible types (e.g int64 can't refer to string),
+// the foreign fields of BelongsTo are declared in the parent and the others are declared in the associated object
+func (as *Association) validateKeyTypes(parentType, associatedType reflect.Type) error {
+	if as.assType == ManyToMany || len(as.referFields) == 0 || len(as.referFields) != len(as.foreignFields) {
+		return nil
+	}
+	referType, foreignType := parentType, associatedType
+	if as.assType == BelongsTo {
+		referType, foreignType = associatedType, parentType
+	}
+	for i := range as.referFields {
+		referField, ok := referType.FieldByName(as.referFields[i])
+		if !ok {
+			continue
+		}
+		foreignField, ok := foreignType.FieldByName(as.foreignFields[i])
+		if !ok {
+			continue
+		}
+		if !reflectutil.CompatibleKeyTypes(referField.Type, foreignField.Type) {
+			return fmt.Errorf("association: field(%s), refer field(%s) of type %s and foreign field(%s) of type %s are incompatible",
+				as.fieldName, as.referFields[i], referField.Type, as.foreignFields[i], foreignField.Type)
+		}
+	}
+	return nil
+}
+
+func (as *Association) buildPolymorphicValue(insert bool) (*foreignFieldValue, error) {
+	if as.polymorphic == nil {
+		return nil, nil
+	}
+	if as.polymorphic.typeField == "" {
+		return nil, fmt.Errorf("association(polymorphic): field(%s), type field is empty", as.fieldName)
+	}
+	if insert && as.polymorphic.typeCol == "" {
+		return nil, fmt.Errorf("association(polymorphic): field(%s), type column is empty", as.fieldName)
+	}
+	return &foreignFieldValue{
+		val:       as.polymorphic.typeValue,
+		fieldName: as.polymorphic.typeField,
+		colName:   as.polymorphic.typeCol,
+	}, nil
+}
+
+func (as *Association) getFactory(parent *Factory) (*Factory, error) {
+	factory := as.factory
+	if as.self {
+		var err error
+		factory, err = parent.selfFactory(as)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("association: field(%s), associated factory is nil", as.fieldName)
+	}
+	if !as.self {
+		// the depth and insert jobs are kept by the clone, so the concurrent builds don't share them
+		factory = factory.Clone()
 	}
-	return nil, nil
+
+	factory.level = parent.level + 1
+	if factory.level > options.maxDepth() {
+		return nil, fmt.Errorf("association: field(%s), depth of associations exceeds the maximum depth(%d), associations may be cyclic", as.fieldName, options.maxDepth())
+	}
+	return factory, nil
 }
 
-func (as *Association) build(val reflect.Value, insert bool, parent *Factory) ([]interface{}, error) {
+// build build the associated objects of parent, it returns the association used by the build, whose keys are inferred
+// into a copy if Infer is set, so the association shared by the factories and concurrent builds is unchanged
+func (as *Association) build(val reflect.Value, insert bool, parent *Factory) (*Association, []interface{}, error) {
 	objects := make([]interface{}, as.num)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
 
+	factory, err := as.getFactory(parent)
+	if err != nil {
+		return as, nil, err
+	}
+	if as.infer {
+		inferred := *as
+		if err := inferred.inferKeys(val.Type(), parent, factory); err != nil {
+			return as, nil, err
+		}
+		as = &inferred
+	}
+	if err := as.validateKeyTypes(val.Type(), factory.objectType()); err != nil {
+		return as, nil, err
+	}
+
 	for i := range objects {
 		var (
 			object interface{}
 			err    error
-			fv     *foreignFieldValue
+			fvs    []*foreignFieldValue
+			typeFV *foreignFieldValue
 		)
-		if as.assType == HasMany || as.assType == HasOne {
-			fv, err = as.buildForeignFieldValue(val)
+		if as.assType == HasMany || as.assType == HasOne || as.assType == BelongsTo {
+			typeFV, err = as.buildPolymorphicValue(insert)
 			if err != nil {
-				return nil, err
+				return as, nil, err
 			}
 		}
 
-		object, _, err = as.factory.build(insert, fv)
+		if as.assType == HasMany || as.assType == HasOne {
+			fvs, err = as.buildForeignFieldValues(val, insert)
+			if err != nil {
+				return as, nil, err
+			}
+			object, _, err = factory.build(insert, i, append(fvs, typeFV)...)
+		} else {
+			object, _, err = factory.build(insert, i)
+		}
 		if err != nil {
-			return nil, err
+			return as, nil, err
 		}
 
 		if as.assType == BelongsTo {
 			err := as.setForeignField(object, val)
 			if err != nil {
-				return nil, err
+				return as, nil, err
+			}
+			if typeFV != nil {
+				if err := typeFV.SetupObject(val.Addr()); err != nil {
+					return as, nil, err
+				}
 			}
 		}
 
 		if as.assType == ManyToMany {
 			err := as.setAssociatedField(object, val)
 			if err != nil {
-				return nil, err
+				return as, nil, err
+			}
+		} else if as.inverseField != "" {
+			err := as.setInverseField(object, val, as.inverseField)
+			if err != nil {
+				return as, nil, err
 			}
 		}
 		objects[i] = object
 	}
 
 	if insert {
-		parent.insertJobsQueue.q.Enqueue(as.factory.insertJobsQueue.q.head)
-		as.factory.insertJobsQueue.clear()
+		parent.insertJobsQueue.q.Enqueue(factory.insertJobsQueue.q.head)
+		factory.insertJobsQueue.clear()
 	}
 
 	if as.assType == HasOne || as.assType == BelongsTo {
 		if err := as.setField(val, objects[0]); err != nil {
-			return objects, err
+			return as, objects, err
 		}
 	}
 
 	if as.assType == HasMany || as.assType == ManyToMany {
 		for i := range objects {
 			if err := as.setSlice(val, objects[i]); err != nil {
-				return objects, err
+				return as, objects, err
 			}
 		}
 	}
 
-	return objects, nil
+	return as, objects, nil
 }
 
 func (as *Association) setForeignField(associatedObj interface{}, parentValue reflect.Value) error {
-	var errMsg = "association(n-to-1): fields(%s), set parent's field from belongs to object, "
-	if as.foreignField == "" || as.referField == "" {
+	if len(as.foreignFields) == 0 || len(as.referFields) == 0 {
 		return nil
 	}
+	if err := validateKeys(as.fieldName, "refer fields", as.referFields, "foreign fields", as.foreignFields); err != nil {
+		return err
+	}
 
 	if parentValue.Kind() == reflect.Ptr {
 		parentValue = parentValue.Elem()
 	}
-	parentField := parentValue.FieldByName(as.foreignField)
+	for i := range as.foreignFields {
+		err := as.setForeignFieldValue(associatedObj, parentValue, as.foreignFields[i], as.referFields[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (as *Association) setForeignFieldValue(associatedObj interface{}, parentValue reflect.Value, foreignField, referFieldName string) error {
+	var errMsg = "association(n-to-1): fields(%s), set parent's field from belongs to object, "
+
+	parentField := parentValue.FieldByName(foreignField)
 	if !reflectutil.CanSet(parentField) {
-		return fmt.Errorf(errMsg+"parent field(%s) is unsettable", as.FieldName, as.foreignField)
+		return fmt.Errorf(errMsg+"parent field(%s) is unsettable", as.fieldName, foreignField)
 	}
 
-	referField := reflectutil.GetFieldElem(reflectutil.GetElem(associatedObj), as.referField)
+	referField := reflectutil.GetFieldElem(reflectutil.GetElem(associatedObj), referFieldName)
 	if !referField.IsValid() {
-		return fmt.Errorf(errMsg+"referenced field(%s) is invalid", as.FieldName, as.referField)
+		return fmt.Errorf(errMsg+"referenced field(%s) is invalid", as.fieldName, referFieldName)
 	}
 
 	ok, err := reflectutil.TryScan(parentField, referField.Interface())
@@ -218,7 +432,7 @@ func (as *Association) setForeignField(associatedObj interface{}, parentValue re
 		referField = reflect.ValueOf(interface{}(value))
 	}
 	if !parentField.Type().AssignableTo(referField.Type()) {
-		return fmt.Errorf(errMsg+"referenced field(%s) can't be assigned to %s", as.referField, as.foreignField)
+		return fmt.Errorf(errMsg+"referenced field(%s) can't be assigned to %s", as.fieldName, referFieldName, foreignField)
 	}
 	parentField.Set(referField)
 	return nil
@@ -227,35 +441,64 @@ func (as *Association) setForeignField(associatedObj interface{}, parentValue re
 func (as *Association) setAssociatedField(associatedObj interface{}, parentValue reflect.Value) error {
 	errMsg := "association(m-to-m): field(%s), set parent object to associated field failed, "
 
-	if as.associatedField == "" {
+	associatedField := as.associatedField
+	if associatedField == "" {
+		associatedField = as.inverseField
+	}
+	if associatedField == "" {
 		return fmt.Errorf(errMsg+"associated field empty", as.fieldName)
 	}
 
-	associatedValue := reflect.ValueOf(associatedObj)
-	associatedField := reflectutil.GetFieldElem(associatedValue, as.associatedField)
-	if !associatedField.CanSet() {
-		return fmt.Errorf(errMsg+"associated field(%s) cannot set", as.fieldName, as.associatedField)
-	}
-	if associatedField.Kind() != reflect.Slice {
-		return fmt.Errorf(errMsg+"associated field(%s) is not slice", as.fieldName, as.associatedField)
-	}
-	newField := associatedField
-	if newField.Cap() == 0 {
-		newField = reflect.MakeSlice(associatedField.Type(), 0, 1)
+	field := reflectutil.GetElem(associatedObj).FieldByName(associatedField)
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf(errMsg+"associated field(%s) is not slice", as.fieldName, associatedField)
 	}
+	return as.setInverseField(associatedObj, parentValue, associatedField)
+}
 
-	if newField.Type().Elem().Kind() == reflect.Ptr && parentValue.Kind() != reflect.Ptr {
-		parentValue = parentValue.Addr()
+// setInverseField set parent object to the field of associated object, the parent object will be appended
+// if the field is slice and the parent object is not in the slice yet
+func (as *Association) setInverseField(associatedObj interface{}, parentValue reflect.Value, inverseFieldName string) error {
+	errMsg := "association: field(%s), set parent object to inverse field(%s) failed, "
+
+	inverseField := reflectutil.GetElem(associatedObj).FieldByName(inverseFieldName)
+	if !reflectutil.CanSet(inverseField) {
+		return fmt.Errorf(errMsg+"field cannot set", as.fieldName, inverseFieldName)
 	}
-	if newField.Type().Elem().Kind() != reflect.Ptr && parentValue.Kind() == reflect.Ptr {
+	if parentValue.Kind() == reflect.Ptr {
 		parentValue = parentValue.Elem()
 	}
 
-	if !newField.Type().Elem().AssignableTo(parentValue.Type()) {
-		return fmt.Errorf(errMsg+"parent object's type can't be assigned to associated field(%s)", as.fieldName, as.associatedField)
+	switch inverseField.Kind() {
+	case reflect.Slice:
+		elemType := inverseField.Type().Elem()
+		item := parentValue
+		if elemType.Kind() == reflect.Ptr {
+			item = parentValue.Addr()
+		}
+		if !item.Type().AssignableTo(elemType) {
+			return fmt.Errorf(errMsg+"parent object's type can't be assigned to the element of field", as.fieldName, inverseFieldName)
+		}
+		if elemType.Kind() == reflect.Ptr {
+			for i := 0; i < inverseField.Len(); i++ {
+				if inverseField.Index(i).Pointer() == item.Pointer() {
+					return nil
+				}
+			}
+		}
+		inverseField.Set(reflect.Append(inverseField, item))
+	case reflect.Ptr:
+		item := parentValue.Addr()
+		if !item.Type().AssignableTo(inverseField.Type()) {
+			return fmt.Errorf(errMsg+"parent object's type can't be assigned to field", as.fieldName, inverseFieldName)
+		}
+		inverseField.Set(item)
+	default:
+		if !parentValue.Type().AssignableTo(inverseField.Type()) {
+			return fmt.Errorf(errMsg+"parent object's type can't be assigned to field", as.fieldName, inverseFieldName)
+		}
+		inverseField.Set(parentValue)
 	}
-	newField = reflect.Append(newField, parentValue)
-	associatedField.Set(newField)
 	return nil
 }
 
@@ -272,6 +515,12 @@ func (as *Association) setSlice(val reflect.Value, dependData interface{}) error
 	// if element of slice is not pointer
 	if field.Type().Elem().Kind() != reflect.Ptr {
 		dependVal = dependVal.Elem()
+	} else {
+		for i := 0; i < field.Len(); i++ {
+			if field.Index(i).Pointer() == dependVal.Pointer() {
+				return nil
+			}
+		}
 	}
 
 	newField := field
@@ -320,6 +569,12 @@ func (fv foreignFieldValue) SetupObject(val reflect.Value) error {
 	if !field.CanSet() {
 		return fmt.Errorf("association: field(%s) is unsettable", fv.fieldName)
 	}
+	if !fieldVal.Type().AssignableTo(field.Type()) {
+		if !fieldVal.Type().ConvertibleTo(field.Type()) {
+			return fmt.Errorf("association: value of field(%s) can't be assigned to %s", fv.fieldName, field.Type())
+		}
+		fieldVal = fieldVal.Convert(field.Type())
+	}
 	field.Set(fieldVal)
 	return nil
 }
@@ -358,6 +613,27 @@ func (ass *Associations) clone() *Associations {
 	}
 }
 
+// withSelf keep the self association(fieldName) with the given depth and remove other self associations,
+// the association will be removed if the depth is zero
+func (ass *Associations) withSelf(fieldName string, depth int32) {
+	filter := func(list []*Association) []*Association {
+		filtered := make([]*Association, 0, len(list))
+		for _, as := range list {
+			if as.self {
+				if as.fieldName != fieldName || depth <= 0 {
+					continue
+				}
+				as.depth = depth
+			}
+			filtered = append(filtered, as)
+		}
+		return filtered
+	}
+	ass.belongsTo = filter(ass.belongsTo)
+	ass.hasOneOrMany = filter(ass.hasOneOrMany)
+	ass.manyToMany = filter(ass.manyToMany)
+}
+
 func (ass *Associations) addBelongsTo(as *Association) {
 	ass.belongsTo = append(ass.belongsTo, as)
 }
@@ -374,8 +650,7 @@ func (ass Associations) buildBelongsTo(val reflect.Value, insert bool, parent *F
 	columnValues := make(map[string]interface{})
 
 	for i := range ass.belongsTo {
-		as := ass.belongsTo[i]
-		objects, err := as.build(val, insert, parent)
+		as, objects, err := ass.belongsTo[i].build(val, insert, parent)
 		if err != nil {
 			return columnValues, err
 		}
@@ -383,15 +658,23 @@ func (ass Associations) buildBelongsTo(val reflect.Value, insert bool, parent *F
 			return columnValues, fmt.Errorf("association: association object(%s) is empty", as.fieldName)
 		}
 		object := objects[0]
-		if insert && (as.referField == "" || as.foreignKey == "") {
+		if insert && (len(as.referFields) == 0 || len(as.foreignKeys) == 0) {
 			return columnValues, fmt.Errorf("association: insert belongTo object(%s) referenced field or columnName is empty", as.fieldName)
 		}
 		if insert {
-			value := reflectutil.GetFieldValue(object, as.referField)
-			if value == nil {
-				return columnValues, fmt.Errorf("association: belongTo object(%s) referField(%s) is incorrect", as.fieldName, as.referField)
+			if err := validateKeys(as.fieldName, "refer fields", as.referFields, "foreign keys", as.foreignKeys); err != nil {
+				return columnValues, err
+			}
+			for i, referField := range as.referFields {
+				value := reflectutil.GetFieldValue(object, referField)
+				if value == nil {
+					return columnValues, fmt.Errorf("association: belongTo object(%s) referField(%s) is incorrect", as.fieldName, referField)
+				}
+				columnValues[as.foreignKeys[i]] = value
+			}
+			if as.polymorphic != nil {
+				columnValues[as.polymorphic.typeCol] = as.polymorphic.typeValue
 			}
-			columnValues[as.foreignKey] = value
 		}
 	}
 	return columnValues, nil
@@ -399,8 +682,7 @@ func (ass Associations) buildBelongsTo(val reflect.Value, insert bool, parent *F
 
 func (ass Associations) buildHasOneOrMany(val reflect.Value, insert bool, parent *Factory) error {
 	for i := range ass.hasOneOrMany {
-		as := ass.hasOneOrMany[i]
-		_, err := as.build(val, insert, parent)
+		_, _, err := ass.hasOneOrMany[i].build(val, insert, parent)
 		if err != nil {
 			return err
 		}
@@ -410,8 +692,7 @@ func (ass Associations) buildHasOneOrMany(val reflect.Value, insert bool, parent
 
 func (ass Associations) buildManyToMany(val reflect.Value, insert bool, parent *Factory) error {
 	for i := range ass.manyToMany {
-		as := ass.manyToMany[i]
-		objects, err := as.build(val, insert, parent)
+		as, objects, err := ass.manyToMany[i].build(val, insert, parent)
 		if err != nil {
 			return err
 		}
@@ -434,16 +715,20 @@ func (ass Associations) buildJoinInsertJob(as *Association, parentObj, associate
 		return nil, err
 	}
 	colValues := make(map[string]interface{})
-	referVal := reflectutil.GetFieldValue(parentObj, as.referField)
-	if referVal == nil {
-		return nil, fmt.Errorf("association(m-to-m): field(%s), refer field(%s) not found", as.fieldName, as.referField)
+	for i, referField := range as.referFields {
+		referVal := reflectutil.GetFieldValue(parentObj, referField)
+		if referVal == nil {
+			return nil, fmt.Errorf("association(m-to-m): field(%s), refer field(%s) not found", as.fieldName, referField)
+		}
+		colValues[as.referCols[i]] = referVal
 	}
-	colValues[as.referCol] = referVal
-	foreginVal := reflectutil.GetFieldValue(associatedObj, as.foreignField)
-	if foreginVal == nil {
-		return nil, fmt.Errorf("association(m-to-m): field(%s), foreign field(%s) not found", as.fieldName, as.foreignField)
+	for i, foreignField := range as.foreignFields {
+		foreginVal := reflectutil.GetFieldValue(associatedObj, foreignField)
+		if foreginVal == nil {
+			return nil, fmt.Errorf("association(m-to-m): field(%s), foreign field(%s) not found", as.fieldName, foreignField)
+		}
+		colValues[as.foreignKeys[i]] = foreginVal
 	}
-	colValues[as.foreignKey] = foreginVal
 
 	for _, a := range as.joinTable.attrs {
 		val, err := a.Gen(nil)
@@ -459,16 +744,20 @@ func (ass Associations) buildJoinInsertJob(as *Association, parentObj, associate
 }
 
 func (ass Associations) validateManyToManyAss(as *Association) error {
-	if as.joinTable.tableName == "" {
+	if as.joinTable == nil || as.joinTable.tableName == "" {
 		return fmt.Errorf("association(m-to-m): field(%s), join table is empty", as.fieldName)
 	}
 
-	if as.referField == "" || as.referCol == "" {
+	if len(as.referFields) == 0 || len(as.referCols) == 0 {
 		return fmt.Errorf("association(m-to-m): field(%s), refer field or refer column name is empty", as.fieldName)
 	}
 
-	if as.foreignField == "" || as.foreignKey == "" {
+	if len(as.foreignFields) == 0 || len(as.foreignKeys) == 0 {
 		return fmt.Errorf("association(m-to-m): field(%s), foreign field or foreign key is empty", as.fieldName)
 	}
-	return nil
+
+	if err := validateKeys(as.fieldName, "refer fields", as.referFields, "refer columns", as.referCols); err != nil {
+		return err
+	}
+	return validateKeys(as.fieldName, "foreign fields", as.foreignFields, "foreign keys", as.foreignKeys)
 }
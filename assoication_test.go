@@ -0,0 +1,32 @@
+package gofactory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inverseParent struct {
+	ID int64
+}
+
+type inverseChild struct {
+	Parents []*inverseParent
+	Parent  *inverseParent
+}
+
+func TestSetInverseFieldDedupe(t *testing.T) {
+	as := &Association{fieldName: "Children"}
+	parent := &inverseParent{ID: 1}
+	child := &inverseChild{}
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, as.setInverseField(child, reflect.ValueOf(parent).Elem(), "Parents"))
+		require.NoError(t, as.setInverseField(child, reflect.ValueOf(parent).Elem(), "Parent"))
+	}
+	assert.Len(t, child.Parents, 1)
+	assert.Same(t, parent, child.Parents[0])
+	assert.Same(t, parent, child.Parent)
+}
@@ -1,8 +1,11 @@
 package attr
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"reflect"
 
+	"github.com/vx416/gogo-factory/genutil"
 	"github.com/vx416/gogo-factory/reflectutil"
 )
 
@@ -43,48 +46,65 @@ type Attributer interface {
 	GetObject() interface{}
 }
 
+// Context the context of the object under construction, which is passed to the ContextGenerator
+type Context struct {
+	// Object the pointer of the partially built object, the fields of the previous attributes are set
+	Object interface{}
+	// Index the index of the object built by BuildN, InsertN or the HasMany association, it is zero for Build
+	Index int
+	// records the records generated by the groups for the object, the members of group share the record through it
+	records map[*groupAttr]genutil.Record
+}
+
+// NewContext create the context of the object built at index, the context should be created once for each object
+func NewContext(object interface{}, index int) Context {
+	return Context{Object: object, Index: index, records: make(map[*groupAttr]genutil.Record)}
+}
+
+// ContextGenerator the Attributer generating value with the context of the object under construction (e.g attr.Fn)
+type ContextGenerator interface {
+	GenWithContext(ctx Context) (interface{}, error)
+}
+
+// SetField set the field of object with the value generated by the attribute
 func SetField(data interface{}, field reflect.Value, fieldType reflect.StructField, attr Attributer) (interface{}, error) {
-	val, err := attr.Gen(data)
+	return SetFieldWithContext(NewContext(data, 0), field, fieldType, attr)
+}
+
+// SetFieldWithContext set the field of object with the value generated by the attribute with the context
+func SetFieldWithContext(ctx Context, field reflect.Value, fieldType reflect.StructField, attr Attributer) (interface{}, error) {
+	val, err := genWithContext(ctx, attr)
 	if err != nil {
 		return nil, err
 	}
 
-	ok, err := reflectutil.TryScan(field, val)
-	if ok {
-		return val, err
+	if val != nil && reflect.TypeOf(val).AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(val))
+		return val, nil
 	}
 
-	if field.Kind() == reflect.Ptr {
-		if field.IsNil() {
-			field.Set(reflect.New(field.Type().Elem()))
+	// the driver.Valuer (e.g decimal.Decimal) is scanned or converted with its driver value (e.g string)
+	setVal := val
+	if valuer, ok := val.(driver.Valuer); ok {
+		if driverVal, err := valuer.Value(); err == nil {
+			setVal = driverVal
 		}
-		field = field.Elem()
 	}
 
-	switch attr.Kind() {
-	case IntAttr:
-		realVal := val.(int)
-		field.SetInt(int64(realVal))
-	case StringAttr:
-		realVal := val.(string)
-		field.SetString(realVal)
-	case FloatAttr:
-		realVal := val.(float64)
-		field.SetFloat(realVal)
-	case BoolAttr:
-		realVal := val.(bool)
-		field.SetBool(realVal)
-	case BytesAttr:
-		realVal := val.([]byte)
-		field.SetBytes(realVal)
-	case UintAttr:
-		realVal := val.(uint)
-		field.SetUint(uint64(realVal))
-	case TimeAttr:
-		field.Set(reflect.ValueOf(val))
-	case UnknownAttr:
-		field.Set(reflect.ValueOf(val))
+	ok, err := reflectutil.TryScan(field, setVal)
+	if ok {
+		return val, err
 	}
 
+	if err := reflectutil.SetValue(field, setVal); err != nil {
+		return nil, fmt.Errorf("attribute(%s): field(%s), err:%+v", attr.Name(), fieldType.Name, err)
+	}
 	return val, nil
 }
+
+func genWithContext(ctx Context, attr Attributer) (interface{}, error) {
+	if generator, ok := attr.(ContextGenerator); ok {
+		return generator.GenWithContext(ctx)
+	}
+	return attr.Gen(ctx.Object)
+}
@@ -0,0 +1,72 @@
+package attr
+
+import (
+	"fmt"
+
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+// SliceOf create slice attributer, the number of elements is between min and max and each element is generated by
+// genFunc
+func SliceOf[T any](name string, genFunc func() T, min, max int, options ...string) Attributer {
+	sizeGen := genutil.RandInt(min, max)
+	return Of(name, func() []T {
+		res := make([]T, sizeGen())
+		for i := range res {
+			res[i] = genFunc()
+		}
+		return res
+	}, options...)
+}
+
+// MapOf create map attributer, the number of entries is between min and max, the keys are generated by keyGen and
+// the values are generated by valGen, there are fewer entries if keyGen cannot generate enough distinct keys
+func MapOf[K comparable, V any](name string, keyGen func() K, valGen func() V, min, max int, options ...string) Attributer {
+	sizeGen := genutil.RandInt(min, max)
+	return Of(name, func() map[K]V {
+		size := sizeGen()
+		res := make(map[K]V, size)
+		for retries := 0; len(res) < size && retries < size*genutil.DefaultUniqueRetries; retries++ {
+			key := keyGen()
+			if _, ok := res[key]; ok {
+				continue
+			}
+			res[key] = valGen()
+		}
+		return res
+	}, options...)
+}
+
+// Builder the factory building the object (e.g *gofactory.Factory)
+type Builder interface {
+	Build() (interface{}, error)
+}
+
+// Sub create attributer of which value is built by the sub-factory, it is used for the value-type struct field (e.g
+// Address or *Address) which is not an association, the built object is not inserted
+func Sub(name string, builder Builder, options ...string) Attributer {
+	return newTypedAttr(name, func(Context) (interface{}, error) {
+		obj, err := builder.Build()
+		if err != nil {
+			return nil, fmt.Errorf("attribute(%s): build sub-factory object failed, err:%+v", name, err)
+		}
+		return obj, nil
+	}, options)
+}
+
+// SubN create slice attributer of which elements are built by the sub-factory, the number of elements is between min
+// and max, the field can be the slice of struct or pointer (e.g []Address or []*Address)
+func SubN(name string, builder Builder, min, max int, options ...string) Attributer {
+	sizeGen := genutil.RandInt(min, max)
+	return newTypedAttr(name, func(Context) (interface{}, error) {
+		res := make([]interface{}, sizeGen())
+		for i := range res {
+			obj, err := builder.Build()
+			if err != nil {
+				return nil, fmt.Errorf("attribute(%s): build sub-factory object failed, err:%+v", name, err)
+			}
+			res[i] = obj
+		}
+		return res, nil
+	}, options)
+}
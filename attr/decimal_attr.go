@@ -0,0 +1,18 @@
+package attr
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal create decimal attributer with generated function, the value is set to the decimal.Decimal and
+// decimal.NullDecimal fields, and the string and sql.NullString fields with the formatted value
+func Decimal(name string, genFunc func() decimal.Decimal, options ...string) Attributer {
+	return Of(name, genFunc, options...)
+}
+
+// BigInt create big integer attributer with generated function, the value is inserted as the formatted integer
+func BigInt(name string, genFunc func() *big.Int, options ...string) Attributer {
+	return Of(name, genFunc, options...)
+}
@@ -0,0 +1,80 @@
+package attr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fn create attributer of which value is generated with the context of the object under construction, e.g the value
+// depends on the other fields or the index of BuildN
+func Fn[T any](name string, genFunc func(ctx Context) T, options ...string) Attributer {
+	return newTypedAttr(name, func(ctx Context) (T, error) {
+		return genFunc(ctx), nil
+	}, options)
+}
+
+// FnErr create attributer like Fn with generated function which may fail (e.g genutil.Template), the error is returned
+// by Build or Insert
+func FnErr[T any](name string, genFunc func(ctx Context) (T, error), options ...string) Attributer {
+	return newTypedAttr(name, func(ctx Context) (T, error) {
+		val, err := genFunc(ctx)
+		if err != nil {
+			return val, fmt.Errorf("attribute(%s): %w", name, err)
+		}
+		return val, nil
+	}, options)
+}
+
+// FnOf create attributer of which value is generated with the typed object under construction and the index of
+// BuildN, O is the struct type of the factory
+func FnOf[O any, T any](name string, genFunc func(obj *O, index int) T, options ...string) Attributer {
+	return newTypedAttr(name, func(ctx Context) (T, error) {
+		obj, ok := ctx.Object.(*O)
+		if !ok {
+			var zero T
+			return zero, fmt.Errorf("attribute(%s): object %T is not %T", name, ctx.Object, obj)
+		}
+		return genFunc(obj, ctx.Index), nil
+	}, options)
+}
+
+// StrFn create string attributer with generated function receiving the object under construction
+func StrFn(name string, genFunc func(obj interface{}) string, options ...string) Attributer {
+	return Fn(name, func(ctx Context) string { return genFunc(ctx.Object) }, options...)
+}
+
+// StrFnErr create string attributer with generated function receiving the object under construction which may fail
+// (e.g genutil.Template), the error is returned by Build or Insert
+func StrFnErr(name string, genFunc func(obj interface{}) (string, error), options ...string) Attributer {
+	return FnErr(name, func(ctx Context) (string, error) { return genFunc(ctx.Object) }, options...)
+}
+
+// IntFn create int attributer with generated function receiving the object under construction
+func IntFn(name string, genFunc func(obj interface{}) int, options ...string) Attributer {
+	return Fn(name, func(ctx Context) int { return genFunc(ctx.Object) }, options...)
+}
+
+// UintFn create uint attributer with generated function receiving the object under construction
+func UintFn(name string, genFunc func(obj interface{}) uint, options ...string) Attributer {
+	return Fn(name, func(ctx Context) uint { return genFunc(ctx.Object) }, options...)
+}
+
+// FloatFn create float attributer with generated function receiving the object under construction
+func FloatFn(name string, genFunc func(obj interface{}) float64, options ...string) Attributer {
+	return Fn(name, func(ctx Context) float64 { return genFunc(ctx.Object) }, options...)
+}
+
+// BoolFn create bool attributer with generated function receiving the object under construction
+func BoolFn(name string, genFunc func(obj interface{}) bool, options ...string) Attributer {
+	return Fn(name, func(ctx Context) bool { return genFunc(ctx.Object) }, options...)
+}
+
+// TimeFn create time attributer with generated function receiving the object under construction
+func TimeFn(name string, genFunc func(obj interface{}) time.Time, options ...string) Attributer {
+	return Fn(name, func(ctx Context) time.Time { return genFunc(ctx.Object) }, options...)
+}
+
+// BytesFn create bytes attributer with generated function receiving the object under construction
+func BytesFn(name string, genFunc func(obj interface{}) []byte, options ...string) Attributer {
+	return Fn(name, func(ctx Context) []byte { return genFunc(ctx.Object) }, options...)
+}
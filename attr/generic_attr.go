@@ -0,0 +1,139 @@
+package attr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Of create attributer of any type with generated function, the value is assigned to the field if it is assignable,
+// otherwise it is converted to the field type (e.g int64 to Gender, float64 to float32) and the conversion error is
+// returned by Build or Insert
+func Of[T any](name string, genFunc func() T, options ...string) Attributer {
+	return newTypedAttr(name, func(Context) (T, error) {
+		return genFunc(), nil
+	}, options)
+}
+
+// OfErr create attributer of any type with generated function which may fail (e.g genutil.Unique), the error is
+// returned by Build or Insert
+func OfErr[T any](name string, genFunc func() (T, error), options ...string) Attributer {
+	return newTypedAttr(name, func(Context) (T, error) {
+		val, err := genFunc()
+		if err != nil {
+			return val, fmt.Errorf("attribute(%s): %w", name, err)
+		}
+		return val, nil
+	}, options)
+}
+
+func newTypedAttr[T any](name string, genFunc func(ctx Context) (T, error), options []string) Attributer {
+	return &typedAttr[T]{
+		name:    name,
+		colName: getColName(options),
+		genFunc: genFunc,
+	}
+}
+
+// typedAttr the attributer generating the value of T, it keeps no state of the generated values, so the attributer can
+// be shared by the cloned factories and concurrent builds
+type typedAttr[T any] struct {
+	name    string
+	colName string
+	genFunc func(ctx Context) (T, error)
+	process Processor
+}
+
+// GetObject get nil, the object under construction is only passed to the Processor
+func (attr *typedAttr[T]) GetObject() interface{} {
+	return nil
+}
+
+func (attr *typedAttr[T]) Process(procFunc Processor) Attributer {
+	attr.process = procFunc
+	return attr
+}
+
+// GetVal get the zero value, the generated value is only passed to the Processor
+func (attr *typedAttr[T]) GetVal() interface{} {
+	var zero T
+	return zero
+}
+
+// SetVal return error, the generated value can only be set by the Processor
+func (attr *typedAttr[T]) SetVal(val interface{}) error {
+	return fmt.Errorf("set attribute(%s) val: the value can only be set in Process", attr.name)
+}
+
+func (attr *typedAttr[T]) ColName() string {
+	return attr.colName
+}
+
+func (attr *typedAttr[T]) Name() string {
+	return attr.name
+}
+
+func (attr *typedAttr[T]) Kind() Type {
+	var zero T
+	switch interface{}(zero).(type) {
+	case int:
+		return IntAttr
+	case uint:
+		return UintAttr
+	case float64:
+		return FloatAttr
+	case string:
+		return StringAttr
+	case []byte:
+		return BytesAttr
+	case time.Time:
+		return TimeAttr
+	case bool:
+		return BoolAttr
+	default:
+		return UnknownAttr
+	}
+}
+
+func (attr *typedAttr[T]) Gen(data interface{}) (interface{}, error) {
+	return attr.GenWithContext(Context{Object: data})
+}
+
+func (attr *typedAttr[T]) GenWithContext(ctx Context) (interface{}, error) {
+	val, err := attr.genFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if attr.process == nil {
+		return val, nil
+	}
+	generated := &typedValue[T]{typedAttr: attr, val: val, object: ctx.Object}
+	if err := attr.process(generated); err != nil {
+		return nil, err
+	}
+	return generated.val, nil
+}
+
+// typedValue the value generated by typedAttr for one object, it is passed to the Processor to get and set the value
+type typedValue[T any] struct {
+	*typedAttr[T]
+	val    T
+	object interface{}
+}
+
+func (v *typedValue[T]) GetObject() interface{} {
+	return v.object
+}
+
+func (v *typedValue[T]) GetVal() interface{} {
+	return v.val
+}
+
+func (v *typedValue[T]) SetVal(val interface{}) error {
+	realVal, ok := val.(T)
+	if !ok {
+		return fmt.Errorf("set attribute val: val %+v is not %T", val, v.val)
+	}
+
+	v.val = realVal
+	return nil
+}
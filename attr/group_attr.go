@@ -0,0 +1,272 @@
+package attr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+// Grouper the Attributer consisting of several attributes, it is expanded by Expand when it is passed to the factory
+type Grouper interface {
+	Attributes() []Attributer
+}
+
+// Expand expand the attributes of Grouper (e.g Group) into the attributes of fields
+func Expand(attrs ...Attributer) []Attributer {
+	res := make([]Attributer, 0, len(attrs))
+	for _, a := range attrs {
+		if grouper, ok := a.(Grouper); ok {
+			res = append(res, Expand(grouper.Attributes()...)...)
+			continue
+		}
+		res = append(res, a)
+	}
+	return res
+}
+
+// Group create the attributes of several fields populated by one record generated by genFunc, so the values of fields
+// are consistent (e.g city, zip and country). The field is declared as Field, Field:column or Field=Key:column, where
+// Key is the key of record (the last name of field path by default) and column is the column name of field. The record
+// is generated once for each object, and the fields are set, omitted or limited (Omit/Only) separately
+func Group(genFunc func() genutil.Record, fields ...string) Attributer {
+	group := &groupAttr{genFunc: genFunc}
+	for _, field := range fields {
+		member := &memberAttr{group: group}
+		member.name, member.colName = field, ""
+		if i := strings.LastIndex(member.name, ":"); i >= 0 {
+			member.name, member.colName = member.name[:i], member.name[i+1:]
+		}
+		member.key = member.name[strings.LastIndex(member.name, ".")+1:]
+		if i := strings.Index(member.name, "="); i >= 0 {
+			member.name, member.key = member.name[:i], member.name[i+1:]
+		}
+		group.members = append(group.members, member)
+	}
+	return group
+}
+
+// groupAttr generate the record shared by the members, the record of each object is kept in the context of object
+type groupAttr struct {
+	genFunc func() genutil.Record
+	members []*memberAttr
+	process Processor
+	set     *genutil.UniqueSet
+}
+
+func (attr *groupAttr) Attributes() []Attributer {
+	res := make([]Attributer, len(attr.members))
+	for i, member := range attr.members {
+		res[i] = member
+	}
+	return res
+}
+
+func (attr *groupAttr) Name() string {
+	names := make([]string, len(attr.members))
+	for i, member := range attr.members {
+		names[i] = member.name
+	}
+	return strings.Join(names, ",")
+}
+
+func (attr *groupAttr) ColName() string {
+	return ""
+}
+
+func (attr *groupAttr) Kind() Type {
+	return UnknownAttr
+}
+
+func (attr *groupAttr) Process(procFunc Processor) Attributer {
+	attr.process = procFunc
+	return attr
+}
+
+// unique create the copy of group only generating the records not generated before, the group itself is unchanged
+func (attr *groupAttr) unique(maxRetries []int) Attributer {
+	retries := 0
+	if len(maxRetries) > 0 {
+		retries = maxRetries[0]
+	}
+	group := &groupAttr{genFunc: attr.genFunc, process: attr.process, set: genutil.NewUniqueSet(retries)}
+	for _, member := range attr.members {
+		copied := *member
+		copied.group = group
+		group.members = append(group.members, &copied)
+	}
+	return group
+}
+
+// ResetUnique forget the records generated by the group
+func (attr *groupAttr) ResetUnique() {
+	if attr.set != nil {
+		attr.set.Reset()
+	}
+}
+
+func (attr *groupAttr) GetVal() interface{} {
+	return genutil.Record(nil)
+}
+
+func (attr *groupAttr) SetVal(val interface{}) error {
+	return fmt.Errorf("set attribute val: attribute(%s) can only be set in Process", attr.Name())
+}
+
+func (attr *groupAttr) GetObject() interface{} {
+	return nil
+}
+
+func (attr *groupAttr) Gen(data interface{}) (interface{}, error) {
+	return attr.GenWithContext(NewContext(data, 0))
+}
+
+func (attr *groupAttr) GenWithContext(ctx Context) (interface{}, error) {
+	return attr.recordOf(ctx)
+}
+
+// recordOf get the record of object in context, the record is generated if the object has not got one
+func (attr *groupAttr) recordOf(ctx Context) (genutil.Record, error) {
+	if record, ok := ctx.records[attr]; ok {
+		return record, nil
+	}
+
+	gen := func() (interface{}, error) {
+		generated := &groupValue{groupAttr: attr, record: attr.genFunc(), object: ctx.Object}
+		if attr.process != nil {
+			if err := attr.process(generated); err != nil {
+				return nil, err
+			}
+		}
+		return generated.record, nil
+	}
+	var (
+		val interface{}
+		err error
+	)
+	if attr.set != nil {
+		val, err = attr.set.Next(gen)
+		if err != nil {
+			return nil, fmt.Errorf("attribute(%s): %w", attr.Name(), err)
+		}
+	} else if val, err = gen(); err != nil {
+		return nil, err
+	}
+
+	record := val.(genutil.Record)
+	if ctx.records != nil {
+		ctx.records[attr] = record
+	}
+	return record, nil
+}
+
+// groupValue the record generated by groupAttr for one object, it is passed to the Processor to get and set the record
+type groupValue struct {
+	*groupAttr
+	record genutil.Record
+	object interface{}
+}
+
+func (v *groupValue) GetVal() interface{} {
+	return v.record
+}
+
+func (v *groupValue) SetVal(val interface{}) error {
+	record, ok := val.(genutil.Record)
+	if !ok {
+		return fmt.Errorf("set attribute val: val %+v is not genutil.Record", val)
+	}
+	v.record = record
+	return nil
+}
+
+func (v *groupValue) GetObject() interface{} {
+	return v.object
+}
+
+// memberAttr set the field with the value of group record
+type memberAttr struct {
+	group   *groupAttr
+	name    string
+	colName string
+	key     string
+	process Processor
+}
+
+func (attr *memberAttr) Name() string {
+	return attr.name
+}
+
+func (attr *memberAttr) ColName() string {
+	return attr.colName
+}
+
+func (attr *memberAttr) Kind() Type {
+	return UnknownAttr
+}
+
+func (attr *memberAttr) Process(procFunc Processor) Attributer {
+	attr.process = procFunc
+	return attr
+}
+
+// ResetUnique forget the records generated by the group
+func (attr *memberAttr) ResetUnique() {
+	attr.group.ResetUnique()
+}
+
+func (attr *memberAttr) GetVal() interface{} {
+	return nil
+}
+
+func (attr *memberAttr) SetVal(val interface{}) error {
+	return fmt.Errorf("set attribute val: attribute(%s) can only be set in Process", attr.name)
+}
+
+func (attr *memberAttr) GetObject() interface{} {
+	return nil
+}
+
+func (attr *memberAttr) Gen(data interface{}) (interface{}, error) {
+	return attr.GenWithContext(NewContext(data, 0))
+}
+
+func (attr *memberAttr) GenWithContext(ctx Context) (interface{}, error) {
+	record, err := attr.group.recordOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := record[attr.key]
+	if !ok {
+		return nil, fmt.Errorf("attribute(%s): key(%s) not found in group record", attr.name, attr.key)
+	}
+	if attr.process == nil {
+		return val, nil
+	}
+	generated := &memberValue{memberAttr: attr, val: val, object: ctx.Object}
+	if err := attr.process(generated); err != nil {
+		return nil, err
+	}
+	return generated.val, nil
+}
+
+// memberValue the value of member generated for one object, it is passed to the Processor to get and set the value
+type memberValue struct {
+	*memberAttr
+	val    interface{}
+	object interface{}
+}
+
+func (v *memberValue) GetVal() interface{} {
+	return v.val
+}
+
+func (v *memberValue) SetVal(val interface{}) error {
+	v.val = val
+	return nil
+}
+
+func (v *memberValue) GetObject() interface{} {
+	return v.object
+}
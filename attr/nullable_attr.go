@@ -0,0 +1,40 @@
+package attr
+
+import (
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+// Nullable wrap the attributer to generate NULL with the probability nullRatio, the pointer field is set to nil, the
+// sql.Scanner field (e.g sql.NullString, null.String) is scanned from nil, and NULL is inserted into the column
+// instead of omitting it
+func Nullable(attr Attributer, nullRatio float64) Attributer {
+	return &nullableAttr{Attributer: attr, isNull: genutil.RandBool(nullRatio)}
+}
+
+type nullableAttr struct {
+	Attributer
+	isNull func() bool
+}
+
+func (attr *nullableAttr) Process(procFunc Processor) Attributer {
+	attr.Attributer.Process(procFunc)
+	return attr
+}
+
+func (attr *nullableAttr) Gen(data interface{}) (interface{}, error) {
+	return attr.GenWithContext(Context{Object: data})
+}
+
+func (attr *nullableAttr) GenWithContext(ctx Context) (interface{}, error) {
+	if attr.isNull() {
+		return nil, nil
+	}
+	return genWithContext(ctx, attr.Attributer)
+}
+
+// ResetUnique forget the values generated by the wrapped unique attributer (e.g attr.Nullable(attr.Unique(...)))
+func (attr *nullableAttr) ResetUnique() {
+	if resetter, ok := attr.Attributer.(UniqueResetter); ok {
+		resetter.ResetUnique()
+	}
+}
@@ -0,0 +1,72 @@
+package attr
+
+import (
+	"fmt"
+
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+// Unique wrap the attributer to only generate the values not generated before by the factory and the factories cloned
+// from it, it retries maxRetries times (genutil.DefaultUniqueRetries by default) on collision, and Build or Insert
+// return the error wrapping genutil.ErrUniqueExhausted if no new value is found. The attributes of attr.Group make
+// the whole group generate the records not generated before
+func Unique(attr Attributer, maxRetries ...int) Attributer {
+	if u, ok := attr.(uniquer); ok {
+		return u.unique(maxRetries)
+	}
+	return newUniqueAttr(attr, maxRetries)
+}
+
+// UniqueResetter the attributer which forgets the generated values by ResetUnique, e.g the unique attributer and the
+// wrappers of it (e.g attr.Nullable(attr.Unique(...)))
+type UniqueResetter interface {
+	ResetUnique()
+}
+
+// uniquer the attributer which makes itself unique instead of being wrapped by uniqueAttr
+type uniquer interface {
+	unique(maxRetries []int) Attributer
+}
+
+// uniqueAttr the attributer which only generates the values not generated before, the issued values are shared by the
+// factories cloned from the same factory
+type uniqueAttr struct {
+	Attributer
+	set *genutil.UniqueSet
+}
+
+func newUniqueAttr(attr Attributer, maxRetries []int) Attributer {
+	retries := 0
+	if len(maxRetries) > 0 {
+		retries = maxRetries[0]
+	}
+	return &uniqueAttr{Attributer: attr, set: genutil.NewUniqueSet(retries)}
+}
+
+func (attr *uniqueAttr) Process(procFunc Processor) Attributer {
+	attr.Attributer.Process(procFunc)
+	return attr
+}
+
+func (attr *uniqueAttr) unique(maxRetries []int) Attributer {
+	return newUniqueAttr(attr.Attributer, maxRetries)
+}
+
+func (attr *uniqueAttr) Gen(data interface{}) (interface{}, error) {
+	return attr.GenWithContext(Context{Object: data})
+}
+
+func (attr *uniqueAttr) GenWithContext(ctx Context) (interface{}, error) {
+	val, err := attr.set.Next(func() (interface{}, error) {
+		return genWithContext(ctx, attr.Attributer)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("attribute(%s): %w", attr.Name(), err)
+	}
+	return val, nil
+}
+
+// ResetUnique forget the values generated by the attributer
+func (attr *uniqueAttr) ResetUnique() {
+	attr.set.Reset()
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"go/types"
 	"io/fs"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,87 +15,284 @@ import (
 )
 
 var (
-	input   = flag.String("i", "", "Input file or directory. (required)")
-	structs = flag.String("s", "", "The needed struct name, these names should  be concat by comma. (required)")
+	input   = flag.String("i", "", "Input file or directory, the current directory is used by default (e.g run by go:generate). (optional)")
+	structs = flag.String("s", "", "The needed struct name, these names should  be concat by comma. The structs annotated with //factory:gen, or all exported structs with db tags are used by default. (optional)")
 	output  = flag.String("o", "", "Output directory for generated factory code. (optional)")
 	p       = flag.Bool("p", false, "Print the result. (optional)")
+	tag     = flag.String("tag", "", "The struct tag used to resolve column names, db and gorm tags are used by default. (optional)")
+	pkg     = flag.String("package", codegen.DefaultPackage, "The package name of generated code. (optional)")
+	merge   = flag.Bool("merge", false, "Merge into the existing output file and keep the hand-edited code. (optional)")
+	rules   = flag.String("rules", "", "The yaml or json file of rules choosing generators by field or column name. (optional)")
+	ddl     = flag.String("ddl", "", "The sql file of CREATE TABLE statements, the models and factories are generated from the tables instead of go structs. (optional)")
+	dialect = flag.String("dialect", string(codegen.SQLite), "The dialect of -ddl file, sqlite, postgres or mysql. (optional)")
+	models  = flag.String("models", "", "Output directory for the models generated from -ddl file, it is required with -ddl. (optional)")
+
+	// loadedPkgs the type checked packages cached by directory
+	loadedPkgs = make(map[string]*types.Package)
+	// writtenFiles the directories of models whose factories are written into the output file, it avoids the
+	// factories of different directories overwriting the same file
+	writtenFiles = make(map[string]string)
 )
 
+// generatorRules the rules loaded from -rules file
+var generatorRules codegen.Rules
+
 func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	inputInfo, err := os.Stat(*input)
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "factorygen: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *output == "" && !*p {
+		return errors.New("output directory (-o) or print (-p) is required")
+	}
+	if *rules != "" {
+		var err error
+		generatorRules, err = codegen.LoadRules(*rules)
+		if err != nil {
+			return fmt.Errorf("load rules(%s) failed, err:%+v", *rules, err)
+		}
+	}
+
+	if *ddl != "" {
+		return generateDDL()
+	}
+
+	inputPath := *input
+	if inputPath == "" {
+		inputPath = "."
+	}
+	inputInfo, err := os.Stat(inputPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Printf("input(%s) not exists\n", *input)
-			os.Exit(1)
+			return fmt.Errorf("input(%s) not exists", inputPath)
 		}
-		fmt.Printf("input(%s) invalid\n", *input)
-		os.Exit(1)
+		return fmt.Errorf("input(%s) invalid, err:%+v", inputPath, err)
 	}
+
+	generated := 0
 	if inputInfo.IsDir() {
-		filepath.Walk(*input, func(path string, info fs.FileInfo, err error) error {
+		err = filepath.Walk(inputPath, func(path string, info fs.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
 			if !info.IsDir() {
-				parseFile(path)
+				return nil
+			}
+			if name := info.Name(); path != inputPath && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
 			}
-			return nil
+			n, err := generatePackage(inputPath, path)
+			generated += n
+			return err
 		})
+	} else {
+		generated, err = generateFile(inputPath)
+	}
+	if err != nil {
+		return err
+	}
+	if generated == 0 {
+		return fmt.Errorf("no struct found in input(%s), use -s or annotate structs with %s comment", inputPath, codegen.GenAnnotation)
+	}
+	return nil
+}
+
+// generatePackage generate one factory file for the structs of package in dir, the file is named after the package
+// if dir is the root of input, otherwise it is named after the path relative to root (e.g user_model_factory.go of
+// user/model), so the packages of the same name in different directories don't overwrite each other
+func generatePackage(root string, dir string) (int, error) {
+	goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil || len(goFiles) == 0 {
+		return 0, err
+	}
 
-		return
+	fileMeta, err := codegen.ParsePackage(dir, splitStructs()...)
+	if err != nil {
+		return 0, err
+	}
+	if len(fileMeta.Structs) == 0 {
+		return 0, nil
+	}
+	if typesPkg := loadPackage(dir); typesPkg != nil {
+		fileMeta.ResolveTypes(typesPkg)
 	}
-	parseFile(*input)
+	name := fileMeta.Package
+	if rel, err := filepath.Rel(root, dir); err == nil && rel != "." {
+		name = strings.ReplaceAll(filepath.ToSlash(rel), "/", "_")
+	}
+	return len(fileMeta.Structs), generate(fileMeta, dir, name)
 }
 
-func parseFile(filePath string) {
+// generateFile generate the factory file for the structs in the go file
+func generateFile(filePath string) (int, error) {
 	if filepath.Ext(filePath) != ".go" {
-		fmt.Printf("input(%s) should be go file\n", filePath)
-		os.Exit(1)
+		return 0, fmt.Errorf("input(%s) should be go file", filePath)
 	}
 
 	node, err := codegen.ParseFile(filePath)
 	if err != nil {
-		fmt.Printf("parse input content failed, err:%+v", err)
-		os.Exit(1)
+		return 0, fmt.Errorf("parse input(%s) failed, err:%+v", filePath, err)
+	}
+	fileMeta := codegen.ParseFileMeta(node, splitStructs()...)
+	if len(fileMeta.Structs) == 0 {
+		return 0, nil
+	}
+	if typesPkg := loadPackage(filepath.Dir(filePath)); typesPkg != nil {
+		fileMeta.ResolveTypes(typesPkg)
 	}
+	fileName := strings.Split(filepath.Base(filePath), ".")[0]
+	return len(fileMeta.Structs), generate(fileMeta, filepath.Dir(filePath), fileName)
+}
+
+func splitStructs() []string {
 	if *structs == "" {
-		fmt.Println("struct name cannot be empty")
-		os.Exit(1)
+		return nil
 	}
-	modelsName := strings.Split(*structs, ",")
-	fileMeta := codegen.ParseFileMeta(node, modelsName...)
+	return strings.Split(*structs, ",")
+}
 
-	if len(fileMeta.Structs) > 0 {
-		t, err := codegen.GetTempalte(false, fileMeta)
-		if err != nil {
-			fmt.Printf("get factory content failed, err:%+v", err)
-			os.Exit(1)
+// generateDDL generate the models of tables in -ddl file into name.go of models directory, and their factories
+func generateDDL() error {
+	if *models == "" {
+		return errors.New("models directory (-models) is required with -ddl")
+	}
+	if *output != "" && filepath.Clean(*output) == filepath.Clean(*models) {
+		return errors.New("models directory (-models) should be different from output directory (-o)")
+	}
+	sqlDialect, err := codegen.ParseDialect(*dialect)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(*ddl)
+	if err != nil {
+		return fmt.Errorf("read ddl(%s) failed, err:%+v", *ddl, err)
+	}
+	tables, err := codegen.ParseDDL(string(data), sqlDialect)
+	if err != nil {
+		return fmt.Errorf("parse ddl(%s) failed, err:%+v", *ddl, err)
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("no CREATE TABLE statement found in ddl(%s)", *ddl)
+	}
+
+	name := strings.Split(filepath.Base(*ddl), ".")[0]
+	fileMeta := codegen.DDLFileMeta(tables, sqlDialect)
+	fileMeta.Package = filepath.Base(*models)
+	src, err := codegen.GetModelTemplate(tables, sqlDialect, fileMeta.Package)
+	if err != nil {
+		return fmt.Errorf("get models content of ddl(%s) failed, err:%+v", *ddl, err)
+	}
+	if *p {
+		fmt.Println(*models)
+		fmt.Println(src)
+	}
+	if *output != "" {
+		if err := os.MkdirAll(*models, 0755); err != nil {
+			return fmt.Errorf("create models(%s) directory failed, err:%+v", *models, err)
 		}
-		if *p {
-			fmt.Println(filePath)
-			fmt.Println(t)
+		modelsPath := filepath.Join(*models, name+".go")
+		if err := ioutil.WriteFile(modelsPath, []byte(src), 0644); err != nil {
+			return fmt.Errorf("write models(%s) file failed, err:%+v", modelsPath, err)
 		}
-		if *output != "" {
-			path := filepath.Base(filePath)
-			fiileName := strings.Split(path, ".")[0]
-			filepath.Join(*output, fiileName)
-			outputFile, err := os.Create(filepath.Join(*output, fiileName+"_factory.go"))
-			if err != nil {
-				fmt.Printf("create output(%s) file failed, err:%+v", *output, err)
-				os.Exit(1)
+	}
+	return generate(fileMeta, *models, name)
+}
+
+// generate generate or merge the factory code of models in dir, the code is written into name_factory.go of output directory
+func generate(fileMeta codegen.FileMeta, dir string, name string) error {
+	var err error
+	fileMeta.ImportPath, err = codegen.ResolveImportPath(dir)
+	if err != nil {
+		return fmt.Errorf("resolve import path of input(%s) failed, err:%+v", dir, err)
+	}
+	cfg := codegen.Config{TagName: *tag, Package: *pkg, Rules: generatorRules}
+	if *output != "" {
+		cfg.ImportPath, _ = codegen.ResolveImportPath(*output)
+	}
+
+	var outputPath, existing string
+	if *output != "" {
+		outputPath = filepath.Join(*output, name+"_factory.go")
+		if written, ok := writtenFiles[outputPath]; ok && written != dir {
+			return fmt.Errorf("output(%s) of input(%s) is written by input(%s) already", outputPath, dir, written)
+		}
+		writtenFiles[outputPath] = dir
+		if *merge {
+			if data, err := ioutil.ReadFile(outputPath); err == nil {
+				existing = string(data)
 			}
-			outputFile.WriteString(t)
 		}
 	}
+
+	var t string
+	if existing != "" {
+		var changes []codegen.Change
+		t, changes, err = codegen.MergeTemplate(existing, fileMeta, cfg)
+		if err != nil {
+			return fmt.Errorf("merge factory content into output(%s) failed, err:%+v", outputPath, err)
+		}
+		fmt.Printf("%s: %d changes\n", outputPath, len(changes))
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+	} else {
+		t, err = codegen.GetTempalte(fileMeta, cfg)
+		if err != nil {
+			return fmt.Errorf("get factory content of input(%s) failed, err:%+v", dir, err)
+		}
+	}
+	if *p {
+		fmt.Println(dir)
+		fmt.Println(t)
+	}
+	if outputPath != "" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			return fmt.Errorf("create output(%s) directory failed, err:%+v", *output, err)
+		}
+		if err := ioutil.WriteFile(outputPath, []byte(t), 0644); err != nil {
+			return fmt.Errorf("write output(%s) file failed, err:%+v", outputPath, err)
+		}
+	}
+	return nil
+}
+
+// loadPackage load the type checked package in dir, nil is returned if the package cannot be loaded
+// and the generated attributes fall back to the type names in AST
+func loadPackage(dir string) *types.Package {
+	if typesPkg, ok := loadedPkgs[dir]; ok {
+		return typesPkg
+	}
+	typesPkg, err := codegen.LoadPackage(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "factorygen: warning: load package(%s) failed, types are guessed by name, err:%+v\n", dir, err)
+	}
+	loadedPkgs[dir] = typesPkg
+	return typesPkg
 }
 
 var (
-	usage = `Usage: gofactory [OPTIONS]
+	usage = `Usage: factorygen [OPTIONS]
 	-i <input_path>
-	-m <models>
+	-s <structs>
 	-o <output_path>
 	-p <only_print>
-	`
+	-tag <column_tag>
+	-package <package_name>
+	-rules <rules_file>
+	-merge <merge_existing>
+	-ddl <sql_file> -dialect <sqlite|postgres|mysql> -models <models_path>
+
+Run by go:generate in the package of models:
+	//go:generate factorygen -o ../factory
+
+`
 )
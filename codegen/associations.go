@@ -0,0 +1,258 @@
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vx416/gogo-factory/internal/tagutil"
+)
+
+// Association the association between the generated models detected from the struct field
+type Association struct {
+	// Kind the method of gofactory.Factory used to add the association (e.g BelongsTo, HasMany, SelfRef)
+	Kind string
+	// Method the name of generated helper method (e.g BelongsToDomain, HasManyBooks, ManyToManyProjects)
+	Method    string
+	FieldName string
+	Model     string
+
+	ReferFields     []string
+	ReferColumns    []string
+	ForeignFields   []string
+	ForeignKeys     []string
+	JoinTable       string
+	AssociatedField string
+}
+
+// Chain get the setter chain configuring the keys of association
+func (as Association) Chain() string {
+	var builder strings.Builder
+	writeKeys := func(singular string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		quoted := make([]string, len(values))
+		for i, value := range values {
+			quoted[i] = strconv.Quote(value)
+		}
+		method := singular
+		if len(values) > 1 {
+			method += "s"
+		}
+		fmt.Fprintf(&builder, ".%s(%s)", method, strings.Join(quoted, ", "))
+	}
+	writeKeys("ReferField", as.ReferFields)
+	writeKeys("ReferColumn", as.ReferColumns)
+	writeKeys("ForeignField", as.ForeignFields)
+	writeKeys("ForeignKey", as.ForeignKeys)
+	if as.AssociatedField != "" {
+		fmt.Fprintf(&builder, ".AssociatedField(%q)", as.AssociatedField)
+	}
+	if as.JoinTable != "" {
+		fmt.Fprintf(&builder, ".JoinTable(%q)", as.JoinTable)
+	}
+	return builder.String()
+}
+
+// ParamName get the parameter name of the associated factory (e.g domainFactory)
+func (as Association) ParamName() string {
+	runes := []rune(as.Model)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes) + "Factory"
+}
+
+// Params get the parameters of generated helper method
+func (as Association) Params() string {
+	switch as.Kind {
+	case "SelfRef":
+		return "depth int32"
+	case "Children":
+		return "num, depth int32"
+	case "HasMany", "ManyToMany":
+		return fmt.Sprintf("%s *%sFactory, num int32", as.ParamName(), as.Model)
+	default:
+		return fmt.Sprintf("%s *%sFactory", as.ParamName(), as.Model)
+	}
+}
+
+// Args get the arguments passed to the method of gofactory.Factory after field name and association
+func (as Association) Args() string {
+	switch as.Kind {
+	case "SelfRef":
+		return ", depth"
+	case "Children":
+		return ", num, depth"
+	case "HasMany", "ManyToMany":
+		return ", num"
+	default:
+		return ""
+	}
+}
+
+// Source get the expression creating the association
+func (as Association) Source() string {
+	if as.Kind == "SelfRef" || as.Kind == "Children" {
+		return "gofactory.SelfAssociation()"
+	}
+	return as.ParamName() + ".ToAssociation()"
+}
+
+// DetectAssociations detect the associations of struct from the fields referring to the given structs,
+// the keys are resolved from gorm tags (foreignKey, references, many2many, joinForeignKey, joinReferences)
+// or the naming conventions (e.g DomainID for field Domain), the fields whose keys can't be resolved are skipped
+func DetectAssociations(st Struct, structs []Struct, tagName string) []Association {
+	models := make(map[string]Struct)
+	for _, model := range structs {
+		models[model.Name] = model
+	}
+
+	associations := make([]Association, 0, 1)
+	for _, field := range st.Fields {
+		model, ok := models[field.Model]
+		if !ok {
+			continue
+		}
+		settings := gormSettings(field.Tag)
+		if _, ignored := settings["-"]; ignored {
+			continue
+		}
+
+		var as Association
+		if field.Slice {
+			as, ok = detectSliceAssociation(st, field, model, settings, tagName)
+		} else {
+			as, ok = detectSingleAssociation(st, field, model, settings, tagName)
+		}
+		if ok {
+			as.FieldName = field.Name
+			as.Model = model.Name
+			associations = append(associations, as)
+		}
+	}
+	return associations
+}
+
+func detectSingleAssociation(st Struct, field Field, model Struct, settings map[string]string, tagName string) (Association, bool) {
+	as := Association{
+		ForeignFields: tagutil.SplitTagValue(settings["FOREIGNKEY"], field.Name+"ID"),
+		ReferFields:   tagutil.SplitTagValue(settings["REFERENCES"], "ID"),
+	}
+	if st.hasFields(as.ForeignFields) && model.hasFields(as.ReferFields) {
+		as.ForeignKeys = st.columns(as.ForeignFields, tagName)
+		if model.Name == st.Name {
+			as.Kind, as.Method = "SelfRef", "With"+field.Name
+		} else {
+			as.Kind, as.Method = "BelongsTo", "BelongsTo"+field.Name
+		}
+		return as, true
+	}
+	if model.Name == st.Name {
+		return as, false
+	}
+
+	as.ForeignFields = tagutil.SplitTagValue(settings["FOREIGNKEY"], st.Name+"ID")
+	if model.hasFields(as.ForeignFields) && st.hasFields(as.ReferFields) {
+		as.ForeignKeys = model.columns(as.ForeignFields, tagName)
+		as.Kind, as.Method = "HasOne", "HasOne"+field.Name
+		return as, true
+	}
+	return as, false
+}
+
+func detectSliceAssociation(st Struct, field Field, model Struct, settings map[string]string, tagName string) (Association, bool) {
+	if joinTable := settings["MANY2MANY"]; joinTable != "" {
+		// the foreignKey and references of gorm many2many tag refer to the current and associated model respectively
+		as := Association{
+			Kind:          "ManyToMany",
+			Method:        "ManyToMany" + field.Name,
+			JoinTable:     joinTable,
+			ReferFields:   tagutil.SplitTagValue(settings["FOREIGNKEY"], "ID"),
+			ForeignFields: tagutil.SplitTagValue(settings["REFERENCES"], "ID"),
+		}
+		as.AssociatedField = model.findSliceField(st.Name)
+		if as.AssociatedField == "" || !st.hasFields(as.ReferFields) || !model.hasFields(as.ForeignFields) {
+			return as, false
+		}
+		as.ReferColumns = tagutil.JoinColumns(settings["JOINFOREIGNKEY"], st.Name, as.ReferFields)
+		as.ForeignKeys = tagutil.JoinColumns(settings["JOINREFERENCES"], model.Name, as.ForeignFields)
+		return as, true
+	}
+
+	as := Association{
+		ForeignFields: tagutil.SplitTagValue(settings["FOREIGNKEY"], st.Name+"ID"),
+		ReferFields:   tagutil.SplitTagValue(settings["REFERENCES"], "ID"),
+	}
+	if model.Name == st.Name && settings["FOREIGNKEY"] == "" {
+		// the children refer to the parent by the foreign field of self belongs-to association (e.g ParentID)
+		for _, selfField := range st.Fields {
+			if selfField.Model != st.Name || selfField.Slice {
+				continue
+			}
+			if selfAs, ok := detectSingleAssociation(st, selfField, st, gormSettings(selfField.Tag), tagName); ok {
+				as.ForeignFields = selfAs.ForeignFields
+				break
+			}
+		}
+	}
+	if !model.hasFields(as.ForeignFields) || !st.hasFields(as.ReferFields) {
+		return as, false
+	}
+	as.ForeignKeys = model.columns(as.ForeignFields, tagName)
+	if model.Name == st.Name {
+		as.Kind, as.Method = "Children", "With"+field.Name
+	} else {
+		as.Kind, as.Method = "HasMany", "HasMany"+field.Name
+	}
+	return as, true
+}
+
+func (st Struct) field(name string) (Field, bool) {
+	for _, field := range st.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+func (st Struct) hasFields(names []string) bool {
+	for _, name := range names {
+		if _, ok := st.field(name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// columns get the column names of fields, the snake case of field name is used if the column is not tagged
+func (st Struct) columns(names []string, tagName string) []string {
+	cols := make([]string, len(names))
+	for i, name := range names {
+		field, _ := st.field(name)
+		cols[i] = field.ColumnName(tagName)
+		if cols[i] == "" {
+			cols[i] = tagutil.ToSnakeCase(name)
+		}
+	}
+	return cols
+}
+
+// findSliceField find the only field which is the slice of the given model
+func (st Struct) findSliceField(model string) string {
+	fields := make([]tagutil.SliceField, 0, len(st.Fields))
+	for _, field := range st.Fields {
+		if field.Slice {
+			fields = append(fields, tagutil.SliceField{Name: field.Name, Elem: field.Model})
+		}
+	}
+	name, _ := tagutil.FindSliceField(fields, model)
+	return name
+}
+
+// gormSettings parse the gorm tag of field into upper case keys and values
+func gormSettings(tag string) map[string]string {
+	return tagutil.ParseGormTag(reflect.StructTag(tag).Get("gorm"))
+}
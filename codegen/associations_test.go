@@ -0,0 +1,52 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAssociations(t *testing.T) {
+	node, err := ParseFile("./testdata/models/models.go")
+	require.NoError(t, err)
+	fm := ParseFileMeta(node, "Domain", "Specialty", "Employee", "Profile", "Project", "Category")
+	require.Len(t, fm.Structs, 6)
+	fm.ImportPath, err = ResolveImportPath("./testdata/models")
+	require.NoError(t, err)
+
+	associations := make(map[string]Association)
+	for _, st := range fm.Structs {
+		for _, as := range DetectAssociations(st, fm.Structs, "") {
+			associations[st.Name+"."+as.FieldName] = as
+		}
+	}
+	assert.Len(t, associations, 6)
+	assert.Equal(t, "HasMany", associations["Domain.Specialties"].Kind)
+	assert.Equal(t, "BelongsTo", associations["Specialty.Domain"].Kind)
+	assert.Equal(t, []string{"domain_id"}, associations["Specialty.Domain"].ForeignKeys)
+	assert.Equal(t, "HasOne", associations["Employee.Profile"].Kind)
+	assert.Equal(t, "ManyToMany", associations["Employee.Projects"].Kind)
+	assert.Equal(t, "Employees", associations["Employee.Projects"].AssociatedField)
+	assert.Equal(t, "SelfRef", associations["Category.Parent"].Kind)
+	assert.Equal(t, "Children", associations["Category.Children"].Kind)
+	assert.Equal(t, []string{"ParentID"}, associations["Category.Children"].ForeignFields)
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `func (f *SpecialtyFactory) BelongsToDomain(domainFactory *DomainFactory) *SpecialtyFactory {
+	ass := domainFactory.ToAssociation().ReferField("ID").ForeignField("DomainID").ForeignKey("domain_id")
+	return &SpecialtyFactory{f.BelongsTo("Domain", ass)}
+}`)
+	assert.Contains(t, res, `func (f *EmployeeFactory) ManyToManyProjects(projectFactory *ProjectFactory, num int32) *EmployeeFactory {
+	ass := projectFactory.ToAssociation().ReferField("ID").ReferColumn("employee_id").ForeignField("ID").ForeignKey("project_id").AssociatedField("Employees").JoinTable("employees_projects")
+	return &EmployeeFactory{f.ManyToMany("Projects", ass, num)}
+}`)
+	assert.Contains(t, res, `func (f *CategoryFactory) WithChildren(num, depth int32) *CategoryFactory {
+	ass := gofactory.SelfAssociation().ReferField("ID").ForeignField("ParentID").ForeignKey("parent_id")
+	return &CategoryFactory{f.Children("Children", ass, num, depth)}
+}`)
+	assert.NotContains(t, res, "HasOneOwner")
+	assert.NotContains(t, res, "BelongsToOwner")
+	typeCheck(t, res)
+}
@@ -1,12 +1,19 @@
 package codegen
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+
+	gofactory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/internal/tagutil"
 )
 
 func ParseFile(filePath string) (*ast.File, error) {
@@ -21,49 +28,246 @@ func ParseFile(filePath string) (*ast.File, error) {
 
 type FileMeta struct {
 	Package string
-	Structs []Struct
+	// ImportPath the import path of the package, it is resolved by ResolveImportPath
+	ImportPath string
+	Structs    []Struct
 }
 
 type Struct struct {
-	Name   string
-	Fields []Field
+	Name      string
+	TableName string
+	Fields    []Field
 }
 
 type Field struct {
 	Name string
 	Type string
+	Tag  string
+	// TypeInfo the type information resolved by ResolveTypes, the generated attribute is guessed by Type if it is nil
+	TypeInfo *TypeInfo
+	// Model the name of struct declared in the same package which the field refers to (e.g Domain of *Domain, []Domain)
+	Model string
+	// Slice the field is a slice of Model
+	Slice bool
+	// Embedded the field is an anonymous struct field whose type is not declared in the parsed files (e.g gorm.Model),
+	// it is flattened by ResolveTypes. the embedded structs declared in the parsed files are flattened by name
+	Embedded bool
+	// Prefix the column prefix of the gorm embeddedPrefix tag of embedded struct
+	Prefix string
+	// DBGenerated the value is generated by database (e.g the auto increment and DEFAULT columns of DDL), the attribute
+	// is not generated but the builder is
+	DBGenerated bool
 }
 
+// ColumnName get column name of the field from the given tag, db tag or gorm column tag
+func (field Field) ColumnName(tagName string) string {
+	tag := reflect.StructTag(field.Tag)
+	tagNames := []string{"db", "gorm"}
+	if tagName != "" {
+		tagNames = []string{tagName}
+	}
+
+	for _, name := range tagNames {
+		var col string
+		if name == "gorm" {
+			col = gofactory.GormTagProcess(tag)
+		} else {
+			col = strings.Split(tag.Get(name), ",")[0]
+		}
+		if col != "" && col != "-" {
+			return field.Prefix + col
+		}
+	}
+	if field.Prefix != "" {
+		return field.Prefix + tagutil.ToSnakeCase(field.Name[strings.LastIndex(field.Name, ".")+1:])
+	}
+	return ""
+}
+
+// GetTableName get the table name from TableName method or the snake case plural of struct name
+func (st Struct) GetTableName() string {
+	if st.TableName != "" {
+		return st.TableName
+	}
+	return tagutil.Pluralize(tagutil.ToSnakeCase(st.Name))
+}
+
+// GenAnnotation the comment annotating the struct to generate factory
+const GenAnnotation = "//factory:gen"
+
+// ParseFileMeta parse the structs of the given names in the file, see ParsePackage for the structs selected if no name is given
 func ParseFileMeta(node *ast.File, structNames ...string) FileMeta {
+	return parseFileMeta([]*ast.File{node}, structNames)
+}
+
+// ParsePackage parse the structs in the go files of dir (test files excluded), the structs are selected by the given
+// names, or the structs annotated with //factory:gen comment, or all exported structs with db tags if none is annotated
+func ParsePackage(dir string, structNames ...string) (FileMeta, error) {
+	filePaths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return FileMeta{}, err
+	}
+	sort.Strings(filePaths)
+
+	nodes := make([]*ast.File, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		node, err := ParseFile(filePath)
+		if err != nil {
+			return FileMeta{}, fmt.Errorf("parse package: parse %s failed, err:%+v", filePath, err)
+		}
+		if len(nodes) > 0 && nodes[0].Name.Name != node.Name.Name {
+			return FileMeta{}, fmt.Errorf("parse package: found packages %s and %s in %s", nodes[0].Name.Name, node.Name.Name, dir)
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return FileMeta{}, fmt.Errorf("parse package: go files not found in %s", dir)
+	}
+	return parseFileMeta(nodes, structNames), nil
+}
+
+// structDecl the struct declared in the parsed files
+type structDecl struct {
+	st        Struct
+	annotated bool
+	hasDBTag  bool
+}
+
+func parseFileMeta(nodes []*ast.File, structNames []string) FileMeta {
 	nameMap := make(map[string]bool)
 	for _, name := range structNames {
-		nameMap[strings.TrimSpace(name)] = true
+		if name = strings.TrimSpace(name); name != "" {
+			nameMap[name] = true
+		}
 	}
 
-	fileMeta := FileMeta{
-		Structs: make([]Struct, 0, len(structNames)),
+	fileMeta := FileMeta{}
+	decls := make([]structDecl, 0, len(structNames))
+	declared := make(map[string]Struct)
+	tableNames := make(map[string]string)
+	for _, node := range nodes {
+		fileMeta.Package = node.Name.Name
+		for _, decl := range node.Decls {
+			switch ret := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range ret.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+						continue
+					}
+					stVisitor := &structVisitor{
+						st: Struct{
+							Name: typeSpec.Name.String(),
+						},
+					}
+					ast.Walk(stVisitor, typeSpec)
+					declared[typeSpec.Name.Name] = stVisitor.st
+					decls = append(decls, structDecl{
+						st:        stVisitor.st,
+						annotated: hasAnnotation(ret.Doc) || hasAnnotation(typeSpec.Doc),
+					})
+				}
+			case *ast.FuncDecl:
+				if recvName, tableName, ok := parseTableNameFunc(ret); ok {
+					tableNames[recvName] = tableName
+				}
+			}
+		}
 	}
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		switch ret := n.(type) {
-		case *ast.TypeSpec:
-			if nameMap[ret.Name.String()] {
-				stVisitor := &structVisitor{
-					st: Struct{
-						Name: ret.Name.String(),
-					},
-				}
-				ast.Walk(stVisitor, n)
-				fileMeta.Structs = append(fileMeta.Structs, stVisitor.st)
+	for i := range decls {
+		decls[i].st.Fields = flattenFields(decls[i].st.Fields, declared, map[string]bool{decls[i].st.Name: true})
+		decls[i].hasDBTag = hasDBTag(decls[i].st)
+	}
+
+	selected := func(decl structDecl) bool { return nameMap[decl.st.Name] }
+	if len(nameMap) == 0 {
+		selected = func(decl structDecl) bool { return decl.annotated }
+		annotated := false
+		for _, decl := range decls {
+			annotated = annotated || decl.annotated
+		}
+		if !annotated {
+			selected = func(decl structDecl) bool { return ast.IsExported(decl.st.Name) && decl.hasDBTag }
+		}
+	}
+
+	fileMeta.Structs = make([]Struct, 0, len(decls))
+	for _, decl := range decls {
+		if !selected(decl) {
+			continue
+		}
+		st := decl.st
+		st.TableName = tableNames[st.Name]
+		for i := range st.Fields {
+			if _, ok := declared[st.Fields[i].Model]; !ok || strings.Contains(st.Fields[i].Name, ".") {
+				st.Fields[i].Model, st.Fields[i].Slice = "", false
 			}
-		case *ast.File:
-			fileMeta.Package = ret.Name.String()
 		}
-		return true
-	})
+		fileMeta.Structs = append(fileMeta.Structs, st)
+	}
 	return fileMeta
 }
 
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == GenAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDBTag(st Struct) bool {
+	for _, field := range st.Fields {
+		if _, ok := reflect.StructTag(field.Tag).Lookup("db"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTableNameFunc get the returned table name from TableName() string method
+func parseTableNameFunc(fn *ast.FuncDecl) (string, string, bool) {
+	if fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+		return "", "", false
+	}
+	recvType := fn.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	recvIdent, ok := recvType.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+
+	for _, stmt := range fn.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		lit, ok := ret.Results[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		tableName, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return "", "", false
+		}
+		return recvIdent.Name, tableName, true
+	}
+	return "", "", false
+}
+
 type structVisitor struct {
 	st  Struct
 	err error
@@ -75,19 +279,118 @@ func (v *structVisitor) Visit(node ast.Node) ast.Visitor {
 	}
 
 	if st, ok := node.(*ast.StructType); ok {
-		v.st.Fields = make([]Field, 0, len(st.Fields.List))
-		for _, field := range st.Fields.List {
-			typeName := getTypeString(field.Type)
-
-			if len(field.Names) > 0 {
-				v.st.Fields = append(v.st.Fields, Field{
-					Type: typeName,
-					Name: field.Names[0].Name,
-				})
+		v.st.Fields = parseStructFields(st, "", "")
+		return nil
+	}
+	return v
+}
+
+// parseStructFields parse the fields of struct, the fields of inline struct types are parsed with the path of field
+// name (e.g Meta.Note), and the column prefix of gorm embeddedPrefix tag
+func parseStructFields(st *ast.StructType, path string, prefix string) []Field {
+	fields := make([]Field, 0, len(st.Fields.List))
+	for _, field := range st.Fields.List {
+		typeName := getTypeString(field.Type)
+
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+
+		if len(field.Names) == 0 {
+			name := embeddedTypeName(field.Type)
+			if name == "" {
+				continue
 			}
+			model, _ := getModelType(field.Type)
+			fields = append(fields, Field{
+				Type:     typeName,
+				Name:     path + name,
+				Tag:      tag,
+				Model:    model,
+				Embedded: true,
+				Prefix:   prefix,
+			})
+			continue
+		}
+
+		fieldType := field.Type
+		if star, ok := fieldType.(*ast.StarExpr); ok {
+			fieldType = star.X
 		}
+		if inline, ok := fieldType.(*ast.StructType); ok {
+			embeddedPrefix := gormSettings(tag)["EMBEDDEDPREFIX"]
+			fields = append(fields, parseStructFields(inline, path+field.Names[0].Name+".", prefix+embeddedPrefix)...)
+			continue
+		}
+
+		model, slice := getModelType(field.Type)
+		fields = append(fields, Field{
+			Type:   typeName,
+			Name:   path + field.Names[0].Name,
+			Tag:    tag,
+			Model:  model,
+			Slice:  slice,
+			Prefix: prefix,
+		})
 	}
-	return v
+	return fields
+}
+
+// embeddedTypeName get the field name of embedded type (e.g Model of *gorm.Model)
+func embeddedTypeName(tye ast.Expr) string {
+	if star, ok := tye.(*ast.StarExpr); ok {
+		tye = star.X
+	}
+	switch fType := tye.(type) {
+	case *ast.Ident:
+		return fType.Name
+	case *ast.SelectorExpr:
+		return fType.Sel.Name
+	default:
+		return ""
+	}
+}
+
+// flattenFields flatten the fields of the embedded structs declared in the parsed files, the promoted fields of
+// anonymous structs keep their names unless they are shadowed by the fields of outer struct, and the fields of
+// the structs tagged by gorm embedded are named by path (e.g Author.Name). the column prefix of gorm embeddedPrefix
+// tag is applied to the flattened fields
+func flattenFields(fields []Field, declared map[string]Struct, visiting map[string]bool) []Field {
+	outer := make(map[string]bool)
+	for _, field := range fields {
+		if !field.Embedded {
+			outer[field.Name] = true
+		}
+	}
+
+	flattened := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		settings := gormSettings(field.Tag)
+		_, gormEmbedded := settings["EMBEDDED"]
+		embedded, ok := declared[field.Model]
+		if !ok || field.Slice || (!field.Embedded && !gormEmbedded) || visiting[embedded.Name] {
+			flattened = append(flattened, field)
+			continue
+		}
+
+		path := field.Name + "."
+		if field.Embedded {
+			path = field.Name[:strings.LastIndex(field.Name, ".")+1]
+		}
+		visiting[embedded.Name] = true
+		for _, nested := range flattenFields(embedded.Fields, declared, visiting) {
+			nested.Name = path + nested.Name
+			nested.Prefix = field.Prefix + settings["EMBEDDEDPREFIX"] + nested.Prefix
+			if field.Embedded && outer[nested.Name] {
+				continue
+			}
+			outer[nested.Name] = true
+			flattened = append(flattened, nested)
+		}
+		delete(visiting, embedded.Name)
+	}
+	return flattened
 }
 
 func getTypeString(tye ast.Expr) string {
@@ -107,3 +410,19 @@ func getTypeString(tye ast.Expr) string {
 		return ""
 	}
 }
+
+// getModelType get the name of type declared in the same package from T, *T, []T or []*T
+func getModelType(tye ast.Expr) (string, bool) {
+	slice := false
+	if arr, ok := tye.(*ast.ArrayType); ok && arr.Len == nil {
+		slice = true
+		tye = arr.Elt
+	}
+	if star, ok := tye.(*ast.StarExpr); ok {
+		tye = star.X
+	}
+	if ident, ok := tye.(*ast.Ident); ok {
+		return ident.Name, slice
+	}
+	return "", false
+}
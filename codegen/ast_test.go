@@ -3,6 +3,7 @@ package codegen
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,8 +14,109 @@ func TestAst(t *testing.T) {
 	fm := ParseFileMeta(node, "User")
 	require.NoError(t, err)
 
-	res, err := GetTempalte(false, fm)
+	res, err := GetTempalte(fm, Config{})
 	require.NoError(t, err)
 	t.Log(res)
 
 }
+
+func TestColumnAndTableName(t *testing.T) {
+	node, err := ParseFile("./user.go")
+	require.NoError(t, err)
+
+	fm := ParseFileMeta(node, "User", "Category")
+	require.Len(t, fm.Structs, 2)
+	user, category := fm.Structs[0], fm.Structs[1]
+	assert.Equal(t, "app_users", user.GetTableName())
+	assert.Equal(t, "categories", category.GetTableName())
+
+	columns := make(map[string]string)
+	for _, field := range user.Fields {
+		columns[field.Name] = field.ColumnName("")
+	}
+	assert.Equal(t, "id", columns["ID"])
+	assert.Equal(t, "gender", columns["Gender"])
+	assert.Equal(t, "", columns["Phone"])
+	assert.Equal(t, "address", columns["Address"])
+	assert.Equal(t, "user_name", user.Fields[1].ColumnName("json"))
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Int("ID", genutil.SeqInt(1, 1), "id"),`)
+	assert.Contains(t, res, `attr.Str("Phone", genutil.RandPhoneE164("")),`)
+	assert.Contains(t, res, `.Table("app_users")}`)
+	assert.Contains(t, res, `.Table("categories")}`)
+}
+
+func TestParsePackage(t *testing.T) {
+	fm, err := ParsePackage("./testdata/annotated")
+	require.NoError(t, err)
+	assert.Equal(t, "annotated", fm.Package)
+	require.Len(t, fm.Structs, 2)
+	assert.Equal(t, "Author", fm.Structs[0].Name)
+	assert.Equal(t, "Book", fm.Structs[1].Name)
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, "func (f *AuthorFactory) HasManyBooks(bookFactory *BookFactory, num int32) *AuthorFactory {")
+	assert.Contains(t, res, "func (f *BookFactory) BelongsToAuthor(authorFactory *AuthorFactory) *BookFactory {")
+
+	fm, err = ParsePackage("./testdata/models")
+	require.NoError(t, err)
+	require.Len(t, fm.Structs, 1)
+	assert.Equal(t, "Specialty", fm.Structs[0].Name)
+
+	fm, err = ParsePackage("./testdata/models", "Domain", "Category")
+	require.NoError(t, err)
+	require.Len(t, fm.Structs, 2)
+
+	_, err = ParsePackage("./testdata")
+	assert.Error(t, err)
+}
+
+func TestParseEmbeddedStructs(t *testing.T) {
+	fileMeta, err := ParsePackage("testdata/embedded")
+	require.NoError(t, err)
+	require.Len(t, fileMeta.Structs, 2)
+
+	blog := fileMeta.Structs[0]
+	names := make([]string, len(blog.Fields))
+	columns := make([]string, len(blog.Fields))
+	for i, field := range blog.Fields {
+		names[i], columns[i] = field.Name, field.ColumnName("")
+	}
+	assert.Equal(t, []string{"ID", "CreatedAt", "UpdatedAt", "Title", "Author.Name", "Author.Email", "Meta.Views"}, names)
+	assert.Equal(t, []string{"id", "created_at", "updated_at", "title", "author_name", "author_email", "views"}, columns)
+
+	post := fileMeta.Structs[1]
+	names = make([]string, len(post.Fields))
+	for i, field := range post.Fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t, []string{"CreatedAt", "UpdatedAt", "Draft", "ID", "Title"}, names)
+	assert.True(t, post.Fields[2].Embedded)
+
+	typesPkg, err := LoadPackage("testdata/embedded")
+	require.NoError(t, err)
+	fileMeta.ResolveTypes(typesPkg)
+	post = fileMeta.Structs[1]
+	names = make([]string, len(post.Fields))
+	for i, field := range post.Fields {
+		names[i] = field.Name
+		require.NotNil(t, field.TypeInfo, field.Name)
+	}
+	assert.Equal(t, []string{"CreatedAt", "UpdatedAt", "ID", "Title"}, names)
+	assert.Equal(t, "post_id", post.Fields[2].ColumnName(""))
+	assert.Equal(t, "Int", fileMeta.Structs[0].Fields[6].TypeInfo.AttrName)
+
+	fileMeta.ImportPath = "github.com/vx416/gogo-factory/codegen/testdata/embedded"
+	res, err := GetTempalte(fileMeta, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),`)
+	assert.Contains(t, res, `attr.Str("Author.Email", genutil.RandEmail(), "author_email"),`)
+	assert.Contains(t, res, `attr.Int("Meta.Views", genutil.SeqInt(1, 1), "views"),`)
+	assert.Contains(t, res, "func (f *BlogFactory) AuthorName(v string) *BlogFactory {")
+	assert.Contains(t, res, `return &BlogFactory{f.Factory.Attrs(attr.Of("Author.Name", gen, "author_name"))}`)
+	assert.Contains(t, res, `attr.Int("ID", genutil.SeqInt(1, 1), "post_id"),`)
+	typeCheck(t, res)
+}
@@ -2,79 +2,324 @@ package codegen
 
 import (
 	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
 	"text/template"
+
+	gofactory "github.com/vx416/gogo-factory"
 )
 
 const factoryTemplate = `
-{{ if .Append | not -}}
-package factory
-
-import (
-    gofactory "github.com/vx416/gogo-factory"
-	"github.com/vx416/gogo-factory/attr"
-	"github.com/vx416/gogo-factory/genutil"
-)
-{{ end -}}
+package {{ .Package }}
 
 {{ range .Structs}}
 type {{.Name}}Factory struct {
 	*gofactory.Factory
 }
 
+{{ .Marker }}
 var {{.Name}} = &{{.Name}}Factory{gofactory.New(
-    &{{.Package}}.{{.Name}}{},
+    &{{.Qualifier}}{{.Name}}{},
     {{- range .Fields}}
-    attr.{{ .Type | GetTypeName }}("{{.Name}}", {{ .Type | GetGenFunc }}),
+    {{ if .Nullable }}attr.Nullable({{ end }}attr.{{ .AttrName }}("{{.Name}}", {{ .GenFunc }}{{ if .Column }}, "{{.Column}}"{{ end }}){{ if .Nullable }}, {{ $.NullRatio }}){{ end }},
     {{- end}}
-)}
+){{ if .Table }}.Table("{{.Table}}"){{ end }}}
+{{ $name := .Name -}}
+{{ range .Associations }}
+func (f *{{$name}}Factory) {{.Method}}({{.Params}}) *{{$name}}Factory {
+	ass := {{.Source}}{{.Chain}}
+	return &{{$name}}Factory{f.{{.Kind}}("{{.FieldName}}", ass{{.Args}})}
+}
+{{end}}
+{{- range .Builders }}
+func (f *{{$name}}Factory) {{.Method}}(v {{.ValueType}}) *{{$name}}Factory {
+	return f.{{.Method}}Gen(func() {{.ValueType}} { return v })
+}
+
+func (f *{{$name}}Factory) {{.Method}}Gen(gen func() {{.ValueType}}) *{{$name}}Factory {
+	return &{{$name}}Factory{f.Factory.Attrs(attr.{{.AttrName}}("{{.Name}}", gen{{ if .Column }}, "{{.Column}}"{{ end }}))}
+}
+{{end}}
 {{end}}
 `
 
-func GetTempalte(append bool, fileMeta FileMeta) (string, error) {
+// DefaultNullRatio the probability of NULL generated for the nullable fields (pointers, sql.Null* and null.*)
+const DefaultNullRatio = 0.1
+
+// DefaultPackage the default package name of generated code
+const DefaultPackage = "factory"
+
+// Config the options of generating factory code
+type Config struct {
+	// TagName the struct tag used to resolve column names, db and gorm tags are used if empty
+	TagName string
+	// Package the package name of generated code, DefaultPackage is used if empty
+	Package string
+	// ImportPath the import path of generated code, it is used to check whether the models are in the same package
+	ImportPath string
+	// Rules the rules of choosing generators, they take precedence over DefaultRules
+	Rules Rules
+}
+
+func (cfg Config) packageName() string {
+	if cfg.Package == "" {
+		return DefaultPackage
+	}
+	return cfg.Package
+}
+
+func GetTempalte(fileMeta FileMeta, cfg Config) (string, error) {
 	var buf = bytes.NewBufferString("")
-	t, err := template.New("").Funcs(map[string]interface{}{
-		"GetTypeName": GetTypeName,
-		"GetGenFunc":  GetGetFunc,
-	}).Parse(factoryTemplate)
+	t, err := template.New("").Parse(factoryTemplate)
 	if err != nil {
 		return "", err
 	}
 
-	data := convertMetaToTemplateData(fileMeta)
-	data["Append"] = append
+	data := convertMetaToTemplateData(fileMeta, cfg)
+	data["Package"] = cfg.packageName()
 	err = t.Execute(buf, data)
 	if err != nil {
 		return "", err
 	}
-	return buf.String(), nil
+
+	src, err := addImports(buf.String(), generatedImports(fileMeta, cfg))
+	if err != nil {
+		return "", err
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", fmt.Errorf("format generated code failed, err:%+v", err)
+	}
+	return string(formatted), nil
+}
+
+// generatedImports get the packages may be referenced by the generated code, the key is the package name
+func generatedImports(fileMeta FileMeta, cfg Config) map[string]string {
+	imports := make(map[string]string)
+	for name, importPath := range knownImports {
+		imports[name] = importPath
+	}
+	for name, importPath := range cfg.Rules.Imports {
+		imports[name] = importPath
+	}
+	for _, st := range fileMeta.Structs {
+		for _, field := range st.Fields {
+			if field.TypeInfo == nil {
+				continue
+			}
+			for name, importPath := range field.TypeInfo.Imports {
+				imports[name] = importPath
+			}
+		}
+	}
+	if qualifier := modelQualifier(fileMeta, cfg); qualifier != "" {
+		imports[qualifier] = fileMeta.ImportPath
+	}
+	return imports
+}
+
+// modelQualifier get the package name used to refer models, it is empty if models and generated code are in the same package
+func modelQualifier(fileMeta FileMeta, cfg Config) string {
+	if fileMeta.ImportPath != "" && fileMeta.ImportPath == cfg.ImportPath {
+		return ""
+	}
+	if fileMeta.ImportPath == "" && fileMeta.Package == cfg.packageName() {
+		return ""
+	}
+	if fileMeta.Package == "" && fileMeta.ImportPath != "" {
+		return path.Base(fileMeta.ImportPath)
+	}
+	return fileMeta.Package
 }
 
-func convertMetaToTemplateData(fileMeta FileMeta) map[string]interface{} {
+func convertMetaToTemplateData(fileMeta FileMeta, cfg Config) map[string]interface{} {
 	res := make(map[string]interface{})
 	structsData := make([]map[string]interface{}, 0, len(fileMeta.Structs))
 
+	qualifier := modelQualifier(fileMeta, cfg)
+	if qualifier != "" {
+		qualifier += "."
+	}
+
 	for _, st := range fileMeta.Structs {
 		structData := map[string]interface{}{
-			"Name":    st.Name,
-			"Package": fileMeta.Package,
+			"Name":      st.Name,
+			"Qualifier": qualifier,
+			"Table":     st.GetTableName(),
+			"Marker":    fieldsMarker(st),
+		}
+		associations := DetectAssociations(st, fileMeta.Structs, cfg.TagName)
+		methods := factoryMethods()
+		for _, as := range associations {
+			methods[as.Method] = true
 		}
+
 		fields := make([]map[string]interface{}, 0, len(st.Fields))
+		builders := make([]map[string]interface{}, 0, len(st.Fields))
 		for _, field := range st.Fields {
-			fields = append(fields, map[string]interface{}{
-				"Name": field.Name,
-				"Type": field.Type,
+			column := field.ColumnName(cfg.TagName)
+			attrName, genFunc, ok := fieldAttr(field, qualifier, column, cfg)
+			if ok && !field.DBGenerated {
+				fields = append(fields, map[string]interface{}{
+					"Name":     field.Name,
+					"AttrName": attrName,
+					"GenFunc":  genFunc,
+					"Column":   column,
+					"Nullable": fieldNullable(field),
+				})
+			}
+			// the builders are generated for the fields skipped by rules too, unless the method names are taken
+			method := builderName(field.Name)
+			if attrName == "" || methods[method] || methods[method+"Gen"] {
+				continue
+			}
+			methods[method], methods[method+"Gen"] = true, true
+			valueType, builderAttr := builderTypes(field, attrName, fileMeta.Package, qualifier)
+			builders = append(builders, map[string]interface{}{
+				"Name":      field.Name,
+				"Method":    method,
+				"AttrName":  builderAttr,
+				"ValueType": valueType,
+				"Column":    column,
 			})
 		}
 		structData["Fields"] = fields
+		structData["Builders"] = builders
+		structData["Associations"] = associations
 		structsData = append(structsData, structData)
 	}
 	res["Structs"] = structsData
+	res["NullRatio"] = DefaultNullRatio
 	return res
 }
 
+// fieldNullable check the field can be NULL, the type name in AST (e.g NullString) is checked if the type information
+// is not resolved
+func fieldNullable(field Field) bool {
+	if field.TypeInfo != nil {
+		return field.TypeInfo.Nullable
+	}
+	return strings.HasPrefix(field.Type, "Null")
+}
+
+// fieldAttr get the attr constructor and generator of the field, the type information resolved by go/types is
+// preferred to the type name in AST. the generator is chosen by the rules of config, the enum constants,
+// DefaultRules and the type of field in order. it returns false if the field is unsupported or skipped
+func fieldAttr(field Field, qualifier string, column string, cfg Config) (string, string, bool) {
+	info := field.TypeInfo
+	if info == nil {
+		info = &TypeInfo{AttrName: GetTypeName(field.Type)}
+		if info.AttrName == "Attr" {
+			info.AttrName = ""
+		}
+	}
+	if info.AttrName == "" {
+		return "", "", false
+	}
+
+	if rule, ok := findRule(cfg.Rules.Rules, field, info.AttrName, column); ok {
+		return info.AttrName, rule.Gen, rule.Gen != SkipGen
+	}
+	switch {
+	case info.Decimal:
+		return "Decimal", decimalGen(info), true
+	case len(info.Enums) > 0:
+		if setFunc, ok := enumSetFuncs[info.AttrName]; ok {
+			values := make([]string, len(info.Enums))
+			for i, enum := range info.Enums {
+				values[i] = fmt.Sprintf("%s(%s%s)", setFunc[1], qualifier, enum)
+			}
+			return info.AttrName, fmt.Sprintf("genutil.%s(%s)", setFunc[0], strings.Join(values, ", ")), true
+		}
+	}
+	if rule, ok := findRule(DefaultRules, field, info.AttrName, column); ok {
+		return info.AttrName, rule.Gen, rule.Gen != SkipGen
+	}
+	if field.TypeInfo == nil {
+		return info.AttrName, GetGetFunc(field.Type), true
+	}
+	return info.AttrName, GetGetFunc(attrGenTypes[info.AttrName]), true
+}
+
+// decimalGen get the generator of decimal field, the values fit the precision and scale of column if they are known
+func decimalGen(info *TypeInfo) string {
+	if info.Precision > 0 {
+		return fmt.Sprintf("genutil.RandNumeric(%d, %d)", info.Precision, info.Scale)
+	}
+	return "genutil.RandDecimal(0, 1000, 2)"
+}
+
+// attrValueTypes the value type generated by the generator of each attr constructor
+var attrValueTypes = map[string]string{
+	"Int":     "int",
+	"Uint":    "uint",
+	"Float":   "float64",
+	"Str":     "string",
+	"Bytes":   "[]byte",
+	"Time":    "time.Time",
+	"Bool":    "bool",
+	"Decimal": "decimal.Decimal",
+}
+
+// builderTypes get the value type of typed builder methods and the attr constructor, the value type is the resolved
+// type of field (e.g int64, *int32, sql.NullString, model.Gender) passed to attr.Of, the value type of attr constructor
+// is used if the type is not resolved. the package of model in the type name is replaced with the qualifier
+func builderTypes(field Field, attrName string, modelPkg string, qualifier string) (string, string) {
+	if field.TypeInfo == nil || field.TypeInfo.TypeName == "" {
+		return attrValueTypes[attrName], attrName
+	}
+	valueType := field.TypeInfo.TypeName
+	if modelPkg != "" {
+		valueType = regexp.MustCompile(`\b`+regexp.QuoteMeta(modelPkg)+`\.`).ReplaceAllString(valueType, qualifier)
+	}
+	return valueType, "Of"
+}
+
+// builderName get the name of typed builder method of the field, the dots of nested field path are removed (e.g AuthorName)
+func builderName(fieldName string) string {
+	return strings.ReplaceAll(fieldName, ".", "")
+}
+
+// factoryMethods get the methods of gofactory.Factory, which are not shadowed by the generated methods
+func factoryMethods() map[string]bool {
+	factoryType := reflect.TypeOf(&gofactory.Factory{})
+	methods := make(map[string]bool, factoryType.NumMethod())
+	for i := 0; i < factoryType.NumMethod(); i++ {
+		methods[factoryType.Method(i).Name] = true
+	}
+	return methods
+}
+
+// enumSetFuncs the generator and conversion used to pick one of enum constants
+var enumSetFuncs = map[string][2]string{
+	"Int":   {"RandIntSet", "int"},
+	"Uint":  {"RandUintSet", "uint"},
+	"Float": {"RandFloatSet", "float64"},
+	"Str":   {"RandStrSet", "string"},
+}
+
+// attrGenTypes the type name passed to GetGetFunc for each attr constructor
+var attrGenTypes = map[string]string{
+	"Int":     "int",
+	"Uint":    "uint",
+	"Float":   "float64",
+	"Str":     "string",
+	"Bytes":   "byte",
+	"Time":    "Time",
+	"Bool":    "bool",
+	"Decimal": "Decimal",
+}
+
 func GetTypeName(in string) string {
 	switch in {
-	case "string", "Decimal", "String", "NullString", "NullDecimal":
+	case "Decimal", "NullDecimal":
+		return "Decimal"
+	case "string", "String", "NullString":
 		return "Str"
 	case "int", "int8", "int32", "int64", "Int", "NullInt64", "NullInt32":
 		return "Int"
@@ -98,7 +343,7 @@ func GetGetFunc(in string) string {
 	case "string", "String", "NullString":
 		return `genutil.RandAlph(10)`
 	case "NullDecimal", "Decimal":
-		return `genutil.FixStr("100.1")`
+		return "genutil.RandDecimal(0, 1000, 2)"
 	case "int", "int8", "int32", "int64", "Int", "NullInt64", "NullInt32":
 		return `genutil.SeqInt(1, 1)`
 	case "uint", "uint8", "uint32", "uint64", "Uint":
@@ -0,0 +1,82 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// typeCheck type check the generated code with the importer shared by LoadPackage
+func typeCheck(t *testing.T, src string) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	file, err := parser.ParseFile(sourceFset, "factory.go", src, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: sourceImporter}
+	_, err = conf.Check(file.Name.Name, sourceFset, []*ast.File{file}, nil)
+	require.NoError(t, err, src)
+}
+
+func TestImports(t *testing.T) {
+	node, err := ParseFile("../example/gencode/model/user.go")
+	require.NoError(t, err)
+	fm := ParseFileMeta(node, "User")
+	fm.ImportPath, err = ResolveImportPath("../example/gencode/model")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/vx416/gogo-factory/example/gencode/model", fm.ImportPath)
+
+	res, err := GetTempalte(fm, Config{Package: "fixtures"})
+	require.NoError(t, err)
+	assert.Contains(t, res, "package fixtures")
+	assert.Contains(t, res, `"time"`)
+	assert.Contains(t, res, `"github.com/vx416/gogo-factory/example/gencode/model"`)
+	assert.Contains(t, res, "&model.User{}")
+	typeCheck(t, res)
+
+	res, err = GetTempalte(fm, Config{Package: "model", ImportPath: fm.ImportPath})
+	require.NoError(t, err)
+	assert.Contains(t, res, "&User{}")
+	assert.NotContains(t, res, `"github.com/vx416/gogo-factory/example/gencode/model"`)
+
+	fm.Structs[0].Fields = fm.Structs[0].Fields[:1]
+	res, err = GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.NotContains(t, res, `"time"`)
+	typeCheck(t, res)
+}
+
+func TestTypedBuilders(t *testing.T) {
+	fm, err := ParsePackage("../example/gencode/model")
+	require.NoError(t, err)
+	fm.ImportPath, err = ResolveImportPath("../example/gencode/model")
+	require.NoError(t, err)
+	pkg, err := LoadPackage("../example/gencode/model")
+	require.NoError(t, err)
+	fm.ResolveTypes(pkg)
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `func (f *UserFactory) Name(v string) *UserFactory {
+	return f.NameGen(func() string { return v })
+}`)
+	assert.Contains(t, res, `func (f *UserFactory) GenderGen(gen func() model.Gender) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Gender", gen, "gender"))}
+}`)
+	assert.Contains(t, res, "func (f *UserFactory) ID(v int64) *UserFactory {")
+	assert.Contains(t, res, "func (f *UserFactory) Address(v sql.NullString) *UserFactory {")
+	assert.Contains(t, res, "func (f *UserFactory) UpdatedAt(v sql.NullTime) *UserFactory {")
+	assert.Contains(t, res, "func (f *UserFactory) CreatedAt(v time.Time) *UserFactory {")
+	assert.Contains(t, res, "func (f *UserFactory) Password(v model.Hash) *UserFactory {")
+	assert.Contains(t, res, "func (f *ProductFactory) DeletedAt(v sql.NullTime) *ProductFactory {")
+	assert.NotContains(t, res, "func (f *ProductFactory) Buyer(")
+	typeCheck(t, res)
+
+	res, err = GetTempalte(fm, Config{Package: "model", ImportPath: fm.ImportPath})
+	require.NoError(t, err)
+	assert.Contains(t, res, "func (f *UserFactory) Gender(v Gender) *UserFactory {")
+	assert.NotContains(t, res, "model.")
+}
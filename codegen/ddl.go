@@ -0,0 +1,510 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Dialect the SQL dialect of DDL
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+)
+
+// ParseDialect parse the dialect name, sqlite3, postgresql, pg and mariadb are accepted as aliases
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql", "pg":
+		return Postgres, nil
+	case "mysql", "mariadb":
+		return MySQL, nil
+	default:
+		return "", fmt.Errorf("dialect(%s) not supported, it should be sqlite, postgres or mysql", name)
+	}
+}
+
+// Table the table declared by CREATE TABLE statement
+type Table struct {
+	Name        string
+	Columns     []Column
+	ForeignKeys []ForeignKey
+}
+
+// Column the column of table
+type Column struct {
+	Name string
+	// Type the upper case type name without arguments (e.g VARCHAR, DOUBLE PRECISION, INT UNSIGNED)
+	Type string
+	// Args the arguments of type (e.g 64 of VARCHAR(64))
+	Args          []string
+	NotNull       bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	// Default the column has the DEFAULT value other than NULL
+	Default bool
+}
+
+// ForeignKey the foreign key constraint of table
+type ForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Nullable check whether the column accepts NULL, the primary key columns are not nullable
+func (col Column) Nullable() bool {
+	return !col.NotNull && !col.PrimaryKey
+}
+
+func (table Table) column(name string) (Column, bool) {
+	for _, col := range table.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// ParseDDL parse the CREATE TABLE statements of DDL, the other statements (e.g DROP TABLE, CREATE INDEX) are ignored
+func ParseDDL(src string, dialect Dialect) ([]Table, error) {
+	p := &ddlParser{tokens: tokenizeDDL(src), dialect: dialect}
+	tables := make([]Table, 0, 1)
+	for !p.eof() {
+		if !p.accept("CREATE") {
+			p.skipStatement()
+			continue
+		}
+		p.accept("OR", "REPLACE")
+		for p.accept("TEMP") || p.accept("TEMPORARY") || p.accept("UNLOGGED") || p.accept("GLOBAL") || p.accept("LOCAL") {
+		}
+		if !p.accept("TABLE") {
+			p.skipStatement()
+			continue
+		}
+		table, ok, err := p.parseTable()
+		if err != nil {
+			return nil, fmt.Errorf("parse ddl: %+v", err)
+		}
+		if ok {
+			tables = append(tables, table)
+		}
+		p.skipStatement()
+	}
+	return tables, nil
+}
+
+type ddlToken struct {
+	text string
+	// quoted the token is quoted identifier (e.g `users`, "users", [users])
+	quoted bool
+}
+
+// tokenizeDDL split the DDL into words, quoted identifiers, string literals and punctuations, the comments are dropped
+func tokenizeDDL(src string) []ddlToken {
+	runes := []rune(src)
+	tokens := make([]ddlToken, 0, len(runes)/4)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '[' && arrayBrackets(tokens, runes, i) > 0:
+			// the brackets following the type name (e.g TEXT[], INT[3]) declare the array type of postgres
+			i += arrayBrackets(tokens, runes, i)
+			tokens = append(tokens, ddlToken{text: "[]"})
+		case r == '`' || r == '"' || r == '[' || r == '\'':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			var builder strings.Builder
+			if r == '\'' {
+				builder.WriteRune(r)
+			}
+			for i++; i < len(runes); i++ {
+				if runes[i] == closing {
+					// the doubled quote is escaped quote
+					if i+1 < len(runes) && runes[i+1] == closing && closing != ']' {
+						builder.WriteRune(closing)
+						i++
+						continue
+					}
+					break
+				}
+				builder.WriteRune(runes[i])
+			}
+			i++
+			if r == '\'' {
+				builder.WriteRune(r)
+			}
+			tokens = append(tokens, ddlToken{text: builder.String(), quoted: r != '\''})
+		case isDDLWordRune(r):
+			start := i
+			for i < len(runes) && isDDLWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, ddlToken{text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, ddlToken{text: string(r)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// arrayBrackets get the length of array brackets at i (e.g [] or [3]) following the type name, it is zero if the
+// brackets are not array brackets (e.g the quoted identifier [users] of sql server)
+func arrayBrackets(tokens []ddlToken, runes []rune, i int) int {
+	if len(tokens) == 0 || tokens[len(tokens)-1].quoted {
+		return 0
+	}
+	if last := tokens[len(tokens)-1].text; last != "[]" && !isDDLWordRune([]rune(last)[0]) {
+		return 0
+	}
+	for j := i + 1; j < len(runes); j++ {
+		switch {
+		case runes[j] == ']':
+			return j - i + 1
+		case !unicode.IsDigit(runes[j]):
+			return 0
+		}
+	}
+	return 0
+}
+
+func isDDLWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+type ddlParser struct {
+	tokens  []ddlToken
+	pos     int
+	dialect Dialect
+}
+
+func (p *ddlParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) peek() ddlToken {
+	if p.eof() {
+		return ddlToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consume the keywords if the following tokens are the keywords in order
+func (p *ddlParser) accept(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if p.pos+i >= len(p.tokens) || !isKeyword(p.tokens[p.pos+i], keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func isKeyword(token ddlToken, keyword string) bool {
+	return !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+// skipStatement skip the tokens until the end of statement
+func (p *ddlParser) skipStatement() {
+	for !p.eof() {
+		token := p.tokens[p.pos]
+		p.pos++
+		if !token.quoted && token.text == ";" {
+			return
+		}
+	}
+}
+
+// parseName parse the qualified name (e.g public.users) and return the last part
+func (p *ddlParser) parseName() string {
+	name := p.peek().text
+	p.pos++
+	for p.peek().text == "." && !p.peek().quoted {
+		p.pos++
+		name = p.peek().text
+		p.pos++
+	}
+	return name
+}
+
+// parseTable parse the table name and definitions, it returns false if the table is created by AS SELECT or LIKE
+func (p *ddlParser) parseTable() (Table, bool, error) {
+	p.accept("IF", "NOT", "EXISTS")
+	table := Table{Name: p.parseName()}
+	if table.Name == "" {
+		return table, false, fmt.Errorf("table name not found")
+	}
+	if p.peek().text != "(" || p.peek().quoted {
+		return table, false, nil
+	}
+	p.pos++
+
+	for _, def := range p.splitDefinitions() {
+		if err := table.addDefinition(def, p.dialect); err != nil {
+			return table, false, fmt.Errorf("table(%s) %+v", table.Name, err)
+		}
+	}
+	if len(table.Columns) == 0 {
+		return table, false, fmt.Errorf("table(%s) has no column", table.Name)
+	}
+	return table, true, nil
+}
+
+// splitDefinitions split the column and constraint definitions by the commas in the parentheses of table
+func (p *ddlParser) splitDefinitions() [][]ddlToken {
+	defs := make([][]ddlToken, 0, 4)
+	depth, start := 0, p.pos
+	for ; !p.eof(); p.pos++ {
+		token := p.tokens[p.pos]
+		if token.quoted {
+			continue
+		}
+		switch token.text {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				defs = append(defs, p.tokens[start:p.pos])
+				p.pos++
+				return defs
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				defs = append(defs, p.tokens[start:p.pos])
+				start = p.pos + 1
+			}
+		case ";":
+			return append(defs, p.tokens[start:p.pos])
+		}
+	}
+	return append(defs, p.tokens[start:p.pos])
+}
+
+func (table *Table) addDefinition(def []ddlToken, dialect Dialect) error {
+	if len(def) == 0 {
+		return nil
+	}
+	if isKeyword(def[0], "CONSTRAINT") {
+		if len(def) < 3 {
+			return fmt.Errorf("invalid constraint")
+		}
+		def = def[2:]
+	}
+
+	first := def[0]
+	switch {
+	case isKeyword(first, "PRIMARY"):
+		cols, _, err := parenNames(def, 2)
+		if err != nil {
+			return fmt.Errorf("primary key: %+v", err)
+		}
+		for _, name := range cols {
+			for i := range table.Columns {
+				if strings.EqualFold(table.Columns[i].Name, name) {
+					table.Columns[i].PrimaryKey = true
+				}
+			}
+		}
+		return nil
+	case isKeyword(first, "FOREIGN"):
+		cols, next, err := parenNames(def, 2)
+		if err != nil {
+			return fmt.Errorf("foreign key: %+v", err)
+		}
+		fk, ok, err := parseReferences(def, next)
+		if err != nil {
+			return fmt.Errorf("foreign key: %+v", err)
+		}
+		if !ok {
+			return fmt.Errorf("invalid foreign key")
+		}
+		fk.Columns = cols
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+		return nil
+	case isKeyword(first, "UNIQUE"), isKeyword(first, "KEY"), isKeyword(first, "INDEX"), isKeyword(first, "CHECK"),
+		isKeyword(first, "EXCLUDE"), isKeyword(first, "FULLTEXT"), isKeyword(first, "SPATIAL"), isKeyword(first, "LIKE"):
+		return nil
+	}
+
+	col, fk, ok, err := parseColumn(def, dialect)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	table.Columns = append(table.Columns, col)
+	if fk != nil {
+		fk.Columns = []string{col.Name}
+		table.ForeignKeys = append(table.ForeignKeys, *fk)
+	}
+	return nil
+}
+
+// columnStopWords the keywords ending the type of column
+var columnStopWords = map[string]bool{
+	"NOT": true, "NULL": true, "PRIMARY": true, "REFERENCES": true, "DEFAULT": true, "UNIQUE": true, "CHECK": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COLLATE": true, "CONSTRAINT": true, "GENERATED": true,
+	"COMMENT": true, "CHARSET": true, "IDENTITY": true, "AS": true, "ON": true, "KEY": true,
+}
+
+// parseColumn parse the column definition, it returns false if the column is computed by expression, and the error
+// if the parentheses are not closed (e.g price NUMERIC(10,)
+func parseColumn(def []ddlToken, dialect Dialect) (Column, *ForeignKey, bool, error) {
+	col := Column{Name: def[0].text}
+	words := make([]string, 0, 2)
+	i := 1
+	for ; i < len(def); i++ {
+		token := def[i]
+		word := strings.ToUpper(token.text)
+		if !token.quoted && token.text == "(" {
+			end, err := closingParen(def, i)
+			if err != nil {
+				return col, nil, false, fmt.Errorf("column(%s): %+v", col.Name, err)
+			}
+			for _, arg := range def[i+1 : end] {
+				if arg.text != "," {
+					col.Args = append(col.Args, arg.text)
+				}
+			}
+			i = end
+			continue
+		}
+		if !token.quoted && (columnStopWords[word] || (word == "CHARACTER" && len(words) > 0)) {
+			break
+		}
+		if !token.quoted && word == "[]" && len(words) > 0 {
+			words[len(words)-1] += word
+			continue
+		}
+		words = append(words, word)
+	}
+	col.Type = strings.Join(words, " ")
+	if strings.HasSuffix(col.Type, "SERIAL") || strings.HasPrefix(col.Type, "SERIAL") {
+		col.AutoIncrement, col.NotNull = true, true
+	}
+
+	var fk *ForeignKey
+	for ; i < len(def); i++ {
+		token := def[i]
+		switch {
+		case token.quoted:
+		case isKeyword(token, "NOT") && i+1 < len(def) && isKeyword(def[i+1], "NULL"):
+			col.NotNull = true
+			i++
+		case isKeyword(token, "PRIMARY"):
+			col.PrimaryKey = true
+			// the INTEGER PRIMARY KEY column of sqlite is the alias of rowid
+			if dialect == SQLite && col.Type == "INTEGER" {
+				col.AutoIncrement = true
+			}
+		case isKeyword(token, "AUTOINCREMENT"), isKeyword(token, "AUTO_INCREMENT"), isKeyword(token, "IDENTITY"):
+			col.AutoIncrement = true
+		case isKeyword(token, "DEFAULT"):
+			col.Default = i+1 >= len(def) || !isKeyword(def[i+1], "NULL")
+			if i+1 < len(def) && def[i+1].text == "(" && !def[i+1].quoted {
+				end, err := closingParen(def, i+1)
+				if err != nil {
+					return col, nil, false, fmt.Errorf("column(%s): %+v", col.Name, err)
+				}
+				i = end
+			} else {
+				i++
+			}
+		case isKeyword(token, "AS"):
+			// the computed column (e.g GENERATED ALWAYS AS (expr) STORED) can't be assigned
+			if i+1 < len(def) && def[i+1].text == "(" && !def[i+1].quoted {
+				return col, nil, false, nil
+			}
+		case isKeyword(token, "REFERENCES"):
+			ref, ok, err := parseReferences(def, i)
+			if err != nil {
+				return col, nil, false, fmt.Errorf("column(%s): %+v", col.Name, err)
+			}
+			if ok {
+				fk = &ref
+			}
+		}
+	}
+	return col, fk, true, nil
+}
+
+// parseReferences parse REFERENCES table(columns) from the index of REFERENCES keyword
+func parseReferences(def []ddlToken, i int) (ForeignKey, bool, error) {
+	if i >= len(def) || !isKeyword(def[i], "REFERENCES") || i+1 >= len(def) {
+		return ForeignKey{}, false, nil
+	}
+	p := &ddlParser{tokens: def, pos: i + 1}
+	fk := ForeignKey{RefTable: p.parseName()}
+	refColumns, _, err := parenNames(def, p.pos)
+	if err != nil {
+		return fk, false, err
+	}
+	fk.RefColumns = refColumns
+	if len(fk.RefColumns) == 0 {
+		fk.RefColumns = []string{"id"}
+	}
+	return fk, true, nil
+}
+
+// parenNames get the names in the parentheses starting at index i, and the index after the closing parenthesis
+func parenNames(def []ddlToken, i int) ([]string, int, error) {
+	if i >= len(def) || def[i].text != "(" || def[i].quoted {
+		return nil, i, nil
+	}
+	end, err := closingParen(def, i)
+	if err != nil {
+		return nil, i, err
+	}
+	names := make([]string, 0, 1)
+	for _, token := range def[i+1 : end] {
+		if isKeyword(token, "ASC") || isKeyword(token, "DESC") {
+			continue
+		}
+		if token.quoted || (token.text != "," && isDDLWordRune([]rune(token.text)[0])) {
+			names = append(names, token.text)
+		}
+	}
+	return names, end + 1, nil
+}
+
+// closingParen get the index of parenthesis closing the one at index i, it returns error if the parenthesis is not closed
+func closingParen(def []ddlToken, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(def); j++ {
+		if def[j].quoted {
+			continue
+		}
+		switch def[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed parenthesis")
+}
@@ -0,0 +1,343 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vx416/gogo-factory/internal/tagutil"
+)
+
+// commonInitialisms the words kept in upper case in go names (e.g user_id to UserID)
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SKU": true, "SQL": true, "SSH": true, "TLS": true, "UID": true,
+	"URI": true, "URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// goName convert the snake case or camel case name to exported go name (e.g host_id to HostID)
+func goName(name string) string {
+	words := strings.FieldsFunc(tagutil.ToSnakeCase(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var builder strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	if builder.Len() == 0 || unicode.IsDigit([]rune(builder.String())[0]) {
+		return "X" + builder.String()
+	}
+	return builder.String()
+}
+
+// singularize get the singular form of the snake case name (e.g categories to category), only the last word is converted
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "zes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return name[:len(name)-1]
+	default:
+		return name
+	}
+}
+
+// columnGoType get the go type and attr constructor of column, the nullable columns are mapped to the sql.Null types,
+// and the attr constructor of array columns is empty
+func columnGoType(col Column, dialect Dialect) (string, TypeInfo) {
+	words := strings.Fields(col.Type)
+	base := ""
+	if len(words) > 0 {
+		base = words[0]
+	}
+	nullable := col.Nullable()
+
+	switch {
+	case strings.HasSuffix(base, "[]"):
+		// the array columns are scanned as the text of array literal (e.g {1,2}), the attribute is not generated
+		if nullable {
+			return "sql.NullString", TypeInfo{TypeName: "sql.NullString", Scanner: true, Nullable: true}
+		}
+		return "string", TypeInfo{TypeName: "string"}
+	case base == "TINYINT" && dialect == MySQL && len(col.Args) == 1 && col.Args[0] == "1",
+		base == "BOOL", base == "BOOLEAN":
+		if nullable {
+			return "sql.NullBool", TypeInfo{AttrName: "Bool", TypeName: "sql.NullBool", Scanner: true, Nullable: true}
+		}
+		return "bool", TypeInfo{AttrName: "Bool", TypeName: "bool"}
+	case sqlIntTypes[base]:
+		if nullable {
+			return "sql.NullInt64", TypeInfo{AttrName: "Int", TypeName: "sql.NullInt64", Scanner: true, Nullable: true}
+		}
+		if strings.Contains(col.Type, "UNSIGNED") {
+			return "uint64", TypeInfo{AttrName: "Uint", TypeName: "uint64"}
+		}
+		return "int64", TypeInfo{AttrName: "Int", TypeName: "int64"}
+	case base == "REAL", base == "DOUBLE", strings.HasPrefix(base, "FLOAT"):
+		if nullable {
+			return "sql.NullFloat64", TypeInfo{AttrName: "Float", TypeName: "sql.NullFloat64", Scanner: true, Nullable: true}
+		}
+		return "float64", TypeInfo{AttrName: "Float", TypeName: "float64"}
+	case base == "DECIMAL", base == "NUMERIC", base == "DEC", base == "MONEY":
+		precision, scale := decimalArgs(col.Args)
+		if nullable {
+			return "sql.NullString", TypeInfo{AttrName: "Decimal", TypeName: "sql.NullString", Scanner: true, Nullable: true,
+				Decimal: true, Precision: precision, Scale: scale}
+		}
+		return "string", TypeInfo{AttrName: "Decimal", TypeName: "string", Decimal: true, Precision: precision, Scale: scale}
+	case base == "DATE", base == "DATETIME", strings.HasPrefix(base, "TIMESTAMP"):
+		if nullable {
+			return "sql.NullTime", TypeInfo{AttrName: "Time", TypeName: "sql.NullTime", Scanner: true, Nullable: true}
+		}
+		return "time.Time", TypeInfo{AttrName: "Time", TypeName: "time.Time"}
+	case strings.HasSuffix(base, "BLOB"), base == "BYTEA", base == "BINARY", base == "VARBINARY":
+		return "[]byte", TypeInfo{AttrName: "Bytes", TypeName: "[]byte"}
+	default:
+		if nullable {
+			return "sql.NullString", TypeInfo{AttrName: "Str", TypeName: "sql.NullString", Scanner: true, Nullable: true}
+		}
+		return "string", TypeInfo{AttrName: "Str", TypeName: "string"}
+	}
+}
+
+// decimalArgs get the precision and scale of DECIMAL(precision, scale), they are zero if they are not declared
+func decimalArgs(args []string) (int, int) {
+	var precision, scale int
+	if len(args) > 0 {
+		precision, _ = strconv.Atoi(args[0])
+	}
+	if len(args) > 1 {
+		scale, _ = strconv.Atoi(args[1])
+	}
+	return precision, scale
+}
+
+var sqlIntTypes = map[string]bool{
+	"INT": true, "INTEGER": true, "TINYINT": true, "SMALLINT": true, "MEDIUMINT": true, "BIGINT": true,
+	"INT2": true, "INT4": true, "INT8": true, "SERIAL": true, "SMALLSERIAL": true, "BIGSERIAL": true,
+	"SERIAL4": true, "SERIAL8": true,
+}
+
+// modelField the field of model generated from table
+type modelField struct {
+	Field
+	// GoType the go type declared in the generated struct (e.g sql.NullString, *User)
+	GoType string
+}
+
+type model struct {
+	Name   string
+	Table  Table
+	Fields []modelField
+}
+
+func (m *model) hasField(name string) bool {
+	for _, field := range m.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueName get the name not taken by the fields of model from the candidates, the last candidate is suffixed if all are taken
+func (m *model) uniqueName(candidates ...string) string {
+	for _, name := range candidates {
+		if !m.hasField(name) {
+			return name
+		}
+	}
+	name := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		if suffixed := fmt.Sprintf("%s%d", name, i); !m.hasField(suffixed) {
+			return suffixed
+		}
+	}
+}
+
+// tableModels convert the tables to models, the columns are converted into the fields tagged with db column names (the
+// auto increment and DEFAULT columns not referred by the foreign keys are generated by database), and
+// the foreign keys referring to the given tables are converted into belongs-to fields of the table and has-many fields
+// of the referred table, which are resolved by DetectAssociations
+func tableModels(tables []Table, dialect Dialect) []*model {
+	referred := make(map[string]bool)
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			for _, col := range fk.RefColumns {
+				referred[strings.ToLower(fk.RefTable+"."+col)] = true
+			}
+		}
+	}
+
+	models := make([]*model, 0, len(tables))
+	byTable := make(map[string]*model)
+	for _, table := range tables {
+		m := &model{Name: goName(singularize(tagutil.ToSnakeCase(table.Name))), Table: table}
+		for _, col := range table.Columns {
+			goType, info := columnGoType(col, dialect)
+			m.Fields = append(m.Fields, modelField{
+				Field: Field{
+					Name:     m.uniqueName(goName(col.Name), goName(col.Name)+"Col"),
+					Type:     astTypeName(goType),
+					Tag:      fmt.Sprintf(`db:"%s"`, col.Name),
+					TypeInfo: &info,
+					// the referred columns are generated, since the associations need them before insert
+					DBGenerated: (col.AutoIncrement || col.Default) && !referred[strings.ToLower(table.Name+"."+col.Name)],
+				},
+				GoType: goType,
+			})
+		}
+		models = append(models, m)
+		byTable[strings.ToLower(table.Name)] = m
+	}
+
+	for _, m := range models {
+		referred := make(map[string]int)
+		for _, fk := range m.Table.ForeignKeys {
+			referred[strings.ToLower(fk.RefTable)]++
+		}
+		for _, fk := range m.Table.ForeignKeys {
+			ref, ok := byTable[strings.ToLower(fk.RefTable)]
+			if !ok || len(fk.Columns) != len(fk.RefColumns) {
+				continue
+			}
+			foreignFields, ok := m.columnFields(fk.Columns)
+			if !ok {
+				continue
+			}
+			referFields, ok := ref.columnFields(fk.RefColumns)
+			if !ok {
+				continue
+			}
+
+			lastCol := tagutil.ToSnakeCase(fk.Columns[len(fk.Columns)-1])
+			candidates := []string{ref.Name}
+			if strings.HasSuffix(lastCol, "_id") && len(lastCol) > 3 {
+				candidates = []string{goName(strings.TrimSuffix(lastCol, "_id")), ref.Name}
+			}
+			belongsTo := m.uniqueName(candidates...)
+			var tag string
+			if len(foreignFields) != 1 || foreignFields[0] != belongsTo+"ID" || len(referFields) != 1 || referFields[0] != "ID" {
+				tag = fmt.Sprintf(`gorm:"foreignKey:%s;references:%s"`, strings.Join(foreignFields, ","), strings.Join(referFields, ","))
+			}
+			m.Fields = append(m.Fields, modelField{
+				Field:  Field{Name: belongsTo, Tag: tag, Model: ref.Name},
+				GoType: "*" + ref.Name,
+			})
+
+			hasMany := goName(tagutil.Pluralize(tagutil.ToSnakeCase(m.Name)))
+			if ref == m {
+				hasMany = "Children"
+			} else if referred[strings.ToLower(fk.RefTable)] > 1 {
+				hasMany = belongsTo + hasMany
+			}
+			hasMany = ref.uniqueName(hasMany)
+			ref.Fields = append(ref.Fields, modelField{
+				Field: Field{
+					Name:  hasMany,
+					Tag:   fmt.Sprintf(`gorm:"foreignKey:%s;references:%s"`, strings.Join(foreignFields, ","), strings.Join(referFields, ",")),
+					Model: m.Name,
+					Slice: true,
+				},
+				GoType: "[]*" + m.Name,
+			})
+		}
+	}
+	return models
+}
+
+// columnFields get the field names of the columns
+func (m *model) columnFields(cols []string) ([]string, bool) {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		found := false
+		for _, field := range m.Fields {
+			if strings.EqualFold(field.ColumnName("db"), col) {
+				names[i], found = field.Name, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+// astTypeName get the type name as getTypeString of the parsed struct (e.g NullString of sql.NullString)
+func astTypeName(goType string) string {
+	if strings.HasPrefix(goType, "*") {
+		return ""
+	}
+	goType = strings.TrimPrefix(goType, "[]")
+	if i := strings.LastIndex(goType, "."); i >= 0 {
+		return goType[i+1:]
+	}
+	return goType
+}
+
+// DDLFileMeta convert the tables to the meta of models generated by GetModelTemplate, the package and import path
+// should be set to the package of generated models
+func DDLFileMeta(tables []Table, dialect Dialect) FileMeta {
+	models := tableModels(tables, dialect)
+	fileMeta := FileMeta{Structs: make([]Struct, 0, len(models))}
+	for _, m := range models {
+		st := Struct{Name: m.Name, TableName: m.Table.Name, Fields: make([]Field, len(m.Fields))}
+		for i, field := range m.Fields {
+			st.Fields[i] = field.Field
+		}
+		fileMeta.Structs = append(fileMeta.Structs, st)
+	}
+	return fileMeta
+}
+
+// GetModelTemplate get the code declaring the structs of tables in the package, the NOT NULL columns are declared as
+// the plain go types, and the nullable columns are declared as the sql.Null types (e.g sql.NullString)
+func GetModelTemplate(tables []Table, dialect Dialect, pkg string) (string, error) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "package %s\n\n", pkg)
+
+	models := tableModels(tables, dialect)
+	imports := make(map[string]bool)
+	for _, m := range models {
+		fmt.Fprintf(&builder, "// %s the model of %s table\n//factory:gen\ntype %s struct {\n", m.Name, m.Table.Name, m.Name)
+		for _, field := range m.Fields {
+			fmt.Fprintf(&builder, "\t%s %s", field.Name, field.GoType)
+			if field.Tag != "" {
+				fmt.Fprintf(&builder, " `%s`", field.Tag)
+			}
+			builder.WriteString("\n")
+			if strings.HasPrefix(field.GoType, "sql.") {
+				imports["database/sql"] = true
+			} else if field.GoType == "time.Time" {
+				imports["time"] = true
+			}
+		}
+		fmt.Fprintf(&builder, "}\n\n// TableName the table name of %s\nfunc (%s) TableName() string {\n\treturn %q\n}\n\n", m.Name, m.Name, m.Table.Name)
+	}
+
+	importPaths := make(map[string]string, len(imports))
+	for importPath := range imports {
+		importPaths[importPath[strings.LastIndex(importPath, "/")+1:]] = importPath
+	}
+	src, err := addImports(builder.String(), importPaths)
+	if err != nil {
+		return "", err
+	}
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", fmt.Errorf("format generated models failed, err:%+v", err)
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,207 @@
+package codegen
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDDL(t *testing.T) {
+	data, err := ioutil.ReadFile("../test/schema/sqlite.sql")
+	require.NoError(t, err)
+	tables, err := ParseDDL(string(data), SQLite)
+	require.NoError(t, err)
+	require.Len(t, tables, 16)
+
+	homes := tables[1]
+	assert.Equal(t, "homes", homes.Name)
+	require.Len(t, homes.Columns, 4)
+	assert.Equal(t, Column{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true}, homes.Columns[0])
+	assert.Equal(t, "location_id", homes.Columns[2].Name)
+	assert.Equal(t, []ForeignKey{
+		{Columns: []string{"host_id"}, RefTable: "user", RefColumns: []string{"id"}},
+		{Columns: []string{"location_id"}, RefTable: "locations", RefColumns: []string{"id"}},
+	}, homes.ForeignKeys)
+
+	organizations := tables[13]
+	assert.True(t, organizations.Columns[0].PrimaryKey)
+	assert.True(t, organizations.Columns[1].PrimaryKey)
+	assert.False(t, organizations.Columns[2].PrimaryKey)
+}
+
+func TestParseDDLDialects(t *testing.T) {
+	tables, err := ParseDDL(`
+-- accounts of users
+CREATE TABLE IF NOT EXISTS public."accounts" (
+	id BIGSERIAL PRIMARY KEY,
+	email CHARACTER VARYING(255) NOT NULL UNIQUE,
+	balance NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	score DOUBLE PRECISION,
+	active BOOLEAN NOT NULL DEFAULT true,
+	avatar BYTEA,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	full_name TEXT GENERATED ALWAYS AS (email || '!') STORED,
+	owner_id BIGINT REFERENCES users (id) ON DELETE CASCADE,
+	CONSTRAINT balance_check CHECK (balance >= 0)
+);
+CREATE INDEX accounts_email ON accounts (email);`, Postgres)
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	accounts := tables[0]
+	assert.Equal(t, "accounts", accounts.Name)
+	require.Len(t, accounts.Columns, 8)
+	assert.Equal(t, Column{Name: "id", Type: "BIGSERIAL", PrimaryKey: true, AutoIncrement: true, NotNull: true}, accounts.Columns[0])
+	assert.Equal(t, Column{Name: "email", Type: "CHARACTER VARYING", Args: []string{"255"}, NotNull: true}, accounts.Columns[1])
+	assert.Equal(t, Column{Name: "balance", Type: "NUMERIC", Args: []string{"10", "2"}, NotNull: true, Default: true}, accounts.Columns[2])
+	assert.Equal(t, "DOUBLE PRECISION", accounts.Columns[3].Type)
+	assert.Equal(t, "TIMESTAMP WITH TIME ZONE", accounts.Columns[6].Type)
+	assert.Equal(t, "owner_id", accounts.Columns[7].Name)
+	assert.Equal(t, []ForeignKey{{Columns: []string{"owner_id"}, RefTable: "users", RefColumns: []string{"id"}}}, accounts.ForeignKeys)
+
+	tables, err = ParseDDL("CREATE TABLE `orders` (\n"+
+		"  `id` INT(11) UNSIGNED NOT NULL AUTO_INCREMENT,\n"+
+		"  `paid` TINYINT(1) NOT NULL DEFAULT '0',\n"+
+		"  `note` VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL COMMENT 'the note',\n"+
+		"  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  KEY `idx_note` (`note`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", MySQL)
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	orders := tables[0]
+	require.Len(t, orders.Columns, 4)
+	assert.Equal(t, Column{Name: "id", Type: "INT UNSIGNED", Args: []string{"11"}, NotNull: true, PrimaryKey: true, AutoIncrement: true}, orders.Columns[0])
+	assert.Equal(t, Column{Name: "note", Type: "VARCHAR", Args: []string{"64"}}, orders.Columns[2])
+	assert.Equal(t, Column{Name: "updated_at", Type: "DATETIME", NotNull: true, Default: true}, orders.Columns[3])
+
+	goType, info := columnGoType(orders.Columns[0], MySQL)
+	assert.Equal(t, "uint64", goType)
+	assert.Equal(t, "Uint", info.AttrName)
+	goType, _ = columnGoType(orders.Columns[1], MySQL)
+	assert.Equal(t, "bool", goType)
+	goType, _ = columnGoType(orders.Columns[2], MySQL)
+	assert.Equal(t, "sql.NullString", goType)
+	goType, info = columnGoType(accounts.Columns[2], Postgres)
+	assert.Equal(t, "string", goType)
+	assert.True(t, info.Decimal)
+	assert.Equal(t, "Decimal", info.AttrName)
+	assert.Equal(t, 10, info.Precision)
+	assert.Equal(t, 2, info.Scale)
+}
+
+func TestParseDDLArrays(t *testing.T) {
+	tables, err := ParseDDL(`CREATE TABLE posts (
+	id BIGSERIAL PRIMARY KEY,
+	tags TEXT[] NOT NULL,
+	scores INT[3][],
+	[title] VARCHAR(64)
+);`, Postgres)
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	posts := tables[0]
+	require.Len(t, posts.Columns, 4)
+	assert.Equal(t, Column{Name: "tags", Type: "TEXT[]", NotNull: true}, posts.Columns[1])
+	assert.Equal(t, Column{Name: "scores", Type: "INT[][]"}, posts.Columns[2])
+	assert.Equal(t, Column{Name: "title", Type: "VARCHAR", Args: []string{"64"}}, posts.Columns[3])
+
+	goType, info := columnGoType(posts.Columns[1], Postgres)
+	assert.Equal(t, "string", goType)
+	assert.Empty(t, info.AttrName)
+	goType, info = columnGoType(posts.Columns[2], Postgres)
+	assert.Equal(t, "sql.NullString", goType)
+	assert.Empty(t, info.AttrName)
+}
+
+func TestParseMalformedDDL(t *testing.T) {
+	_, err := ParseDDL("CREATE TABLE products (id INTEGER PRIMARY KEY, price NUMERIC(10,", Postgres)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table(products) column(price): unclosed parenthesis")
+
+	_, err = ParseDDL("CREATE TABLE products (price NUMERIC(", Postgres)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "column(price)")
+
+	_, err = ParseDDL("CREATE TABLE products (id INTEGER DEFAULT (1", SQLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "column(id)")
+
+	_, err = ParseDDL("CREATE TABLE products (owner_id INTEGER REFERENCES users (id", SQLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "column(owner_id)")
+
+	_, err = ParseDDL("CREATE TABLE products (id INTEGER, PRIMARY KEY (id", SQLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "primary key: unclosed parenthesis")
+}
+
+func TestGenerateFromDDL(t *testing.T) {
+	data, err := ioutil.ReadFile("../test/schema/sqlite.sql")
+	require.NoError(t, err)
+	tables, err := ParseDDL(string(data), SQLite)
+	require.NoError(t, err)
+
+	src, err := GetModelTemplate(tables, SQLite, "model")
+	require.NoError(t, err)
+	assert.Contains(t, src, "type EmployeesProject struct {")
+	assert.Contains(t, src, "CommentableID   int64          `db:\"commentable_id\"`")
+	assert.Contains(t, src, "Username  sql.NullString `db:\"username\"`")
+	assert.Contains(t, src, "Salary            sql.NullFloat64")
+	assert.Contains(t, src, "Specialties       []*Specialty        `gorm:\"foreignKey:OwnerID;references:ID\"`")
+	assert.Contains(t, src, "Children []*Category `gorm:\"foreignKey:ParentID;references:ID\"`")
+	assert.Contains(t, src, "Organization   *Organization  `gorm:\"foreignKey:TenantID,OrganizationID;references:TenantID,ID\"`")
+	assert.Contains(t, src, `return "employees_projects"`)
+
+	typeCheck(t, src)
+
+	fileMeta := DDLFileMeta(tables, SQLite)
+	fileMeta.Package = "model"
+	res, err := GetTempalte(fileMeta, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Nullable(attr.Str("Username", genutil.RandUsername(), "username"), 0.1),`)
+	assert.Contains(t, res, `attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Float("Salary", genutil.RandFloat(0, 10), "salary"), 0.1),`)
+	assert.Contains(t, res, `attr.Decimal("Amount", genutil.RandNumeric(12, 2), "amount"),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Decimal("Discount", genutil.RandNumeric(10, 2), "discount"), 0.1),`)
+	assert.Contains(t, res, `).Table("categories")}`)
+	assert.Contains(t, res, "func (f *HomeFactory) BelongsToLocation(locationFactory *LocationFactory) *HomeFactory {")
+	assert.Contains(t, res, "func (f *LocationFactory) HasManyHomes(homeFactory *HomeFactory, num int32) *LocationFactory {")
+	assert.Contains(t, res, "func (f *CategoryFactory) WithParent(depth int32) *CategoryFactory {")
+	assert.Contains(t, res, "func (f *CategoryFactory) WithChildren(num, depth int32) *CategoryFactory {")
+	assert.Contains(t, res, `ass := organizationFactory.ToAssociation().ReferFields("TenantID", "ID").ForeignFields("TenantID", "OrganizationID").ForeignKeys("tenant_id", "organization_id")`)
+	assert.NotContains(t, res, "BelongsToHost")
+}
+
+func TestGenerateFromDDLGeneratedColumns(t *testing.T) {
+	tables, err := ParseDDL(`
+CREATE TABLE teams (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL
+);
+CREATE TABLE players (
+	id BIGSERIAL PRIMARY KEY,
+	team_id BIGINT NOT NULL REFERENCES teams (id),
+	status TEXT NOT NULL DEFAULT 'active',
+	nickname TEXT DEFAULT NULL,
+	tags TEXT[],
+	created_at TIMESTAMP NOT NULL DEFAULT now()
+);`, Postgres)
+	require.NoError(t, err)
+
+	fileMeta := DDLFileMeta(tables, Postgres)
+	fileMeta.Package = "model"
+	res, err := GetTempalte(fileMeta, Config{})
+	require.NoError(t, err)
+	// the id of teams is referred by players, so it is generated for the associations
+	assert.Contains(t, res, `attr.Int("ID", genutil.SeqInt(1, 1), "id"),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Str("Nickname", genutil.RandAlph(10), "nickname"), 0.1),`)
+	assert.NotContains(t, res, `"status"),`)
+	assert.NotContains(t, res, `"created_at"),`)
+	assert.NotContains(t, res, `"tags"),`)
+	assert.Equal(t, 1, strings.Count(res, `attr.Int("ID"`))
+	// the builders of the columns generated by database are kept to set them explicitly
+	assert.Contains(t, res, "func (f *PlayerFactory) Status(")
+	assert.Contains(t, res, "func (f *PlayerFactory) ID(")
+}
@@ -0,0 +1,164 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// knownImports the packages may be referenced by the generated code, the key is the package name
+var knownImports = map[string]string{
+	"gofactory": "github.com/vx416/gogo-factory",
+	"attr":      "github.com/vx416/gogo-factory/attr",
+	"genutil":   "github.com/vx416/gogo-factory/genutil",
+	"time":      "time",
+	"sql":       "database/sql",
+	"decimal":   "github.com/shopspring/decimal",
+}
+
+// ResolveImportPath resolve the import path of the package in dir by the module path in go.mod
+func ResolveImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for modDir := absDir; ; modDir = filepath.Dir(modDir) {
+		modPath, err := readModulePath(filepath.Join(modDir, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(modDir, absDir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modPath, nil
+			}
+			return path.Join(modPath, filepath.ToSlash(rel)), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		if filepath.Dir(modDir) == modDir {
+			return "", fmt.Errorf("resolve import path: go.mod not found in %s or any parent directory", absDir)
+		}
+	}
+}
+
+func readModulePath(modFile string) (string, error) {
+	file, err := os.Open(modFile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		modPath := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if i := strings.Index(modPath, "//"); i >= 0 {
+			modPath = strings.TrimSpace(modPath[:i])
+		}
+		if unquoted, err := strconv.Unquote(modPath); err == nil {
+			modPath = unquoted
+		}
+		if modPath != "" {
+			return modPath, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("resolve import path: module path not found in %s", modFile)
+}
+
+// addImports add the import declaration of the packages which are referenced by src and not imported yet,
+// the import specs are inserted into the existing import declaration if there is one
+func addImports(src string, imports map[string]string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return "", fmt.Errorf("add imports: parse generated code failed, err:%+v", err)
+	}
+
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			imported[spec.Name.Name] = true
+		} else {
+			imported[path.Base(importPath)] = true
+		}
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil && !imported[ident.Name] {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	stdSpecs, otherSpecs := make([]string, 0, 1), make([]string, 0, len(used))
+	for name := range used {
+		importPath, ok := imports[name]
+		if !ok {
+			continue
+		}
+		spec := strconv.Quote(importPath)
+		if path.Base(importPath) != name {
+			spec = name + " " + spec
+		}
+		if strings.Contains(importPath, ".") {
+			otherSpecs = append(otherSpecs, spec)
+		} else {
+			stdSpecs = append(stdSpecs, spec)
+		}
+	}
+	if len(stdSpecs)+len(otherSpecs) == 0 {
+		return src, nil
+	}
+	sort.Strings(stdSpecs)
+	sort.Strings(otherSpecs)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || !genDecl.Lparen.IsValid() {
+			continue
+		}
+		var specs strings.Builder
+		for _, spec := range append(stdSpecs, otherSpecs...) {
+			specs.WriteString("\t" + spec + "\n")
+		}
+		offset := fset.Position(genDecl.Rparen).Offset
+		return src[:offset] + specs.String() + src[offset:], nil
+	}
+
+	var importDecl strings.Builder
+	importDecl.WriteString("\n\nimport (\n")
+	for _, spec := range stdSpecs {
+		importDecl.WriteString("\t" + spec + "\n")
+	}
+	if len(stdSpecs) > 0 && len(otherSpecs) > 0 {
+		importDecl.WriteString("\n")
+	}
+	for _, spec := range otherSpecs {
+		importDecl.WriteString("\t" + spec + "\n")
+	}
+	importDecl.WriteString(")\n")
+
+	offset := fset.Position(file.Name.End()).Offset
+	return src[:offset] + importDecl.String() + src[offset:], nil
+}
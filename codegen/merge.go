@@ -0,0 +1,447 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fieldsMarkerPrefix the prefix of comment recording the fields of struct when the factory was generated
+const fieldsMarkerPrefix = "//factorygen:fields "
+
+// ChangeKind the kind of change made by MergeTemplate
+type ChangeKind string
+
+const (
+	// AddFactory the factory of new struct is added
+	AddFactory ChangeKind = "add factory"
+	// AddAttribute the attribute of new field is added
+	AddAttribute ChangeKind = "add attribute"
+	// RemoveAttribute the attribute of deleted field is removed
+	RemoveAttribute ChangeKind = "remove attribute"
+	// AddMethod the association helper method or typed builder method is added
+	AddMethod ChangeKind = "add method"
+	// RemoveMethod the typed builder method of deleted field is removed
+	RemoveMethod ChangeKind = "remove method"
+)
+
+// Change the change made to the existing factory code by MergeTemplate
+type Change struct {
+	Kind    ChangeKind
+	Factory string
+	Name    string
+}
+
+func (change Change) String() string {
+	if change.Name == "" {
+		return fmt.Sprintf("%s %s", change.Kind, change.Factory)
+	}
+	return fmt.Sprintf("%s %s.%s", change.Kind, change.Factory, change.Name)
+}
+
+func fieldsMarker(st Struct) string {
+	names := make([]string, len(st.Fields))
+	for i, field := range st.Fields {
+		names[i] = field.Name
+	}
+	return fieldsMarkerPrefix + strings.Join(names, ",")
+}
+
+// MergeTemplate merge the generated factories into the existing factory code, the attributes of new fields and the
+// factories of new structs are added, the attributes of deleted fields are removed, while the hand-edited attributes,
+// methods and declarations are kept. the fields known by last generation are recorded by the //factorygen:fields
+// comment, so the attributes removed by hand are not added again
+func MergeTemplate(existing string, fileMeta FileMeta, cfg Config) (string, []Change, error) {
+	generated, err := GetTempalte(fileMeta, cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	genFset := token.NewFileSet()
+	genFile, err := parser.ParseFile(genFset, "", generated, parser.ParseComments)
+	if err != nil {
+		return "", nil, fmt.Errorf("merge factory: parse generated code failed, err:%+v", err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", existing, parser.ParseComments)
+	if err != nil {
+		return "", nil, fmt.Errorf("merge factory: parse existing code failed, err:%+v", err)
+	}
+
+	m := &merger{
+		src:     existing,
+		fset:    fset,
+		file:    file,
+		genSrc:  generated,
+		genFset: genFset,
+		genFile: genFile,
+	}
+	for _, st := range fileMeta.Structs {
+		m.mergeFactory(st)
+	}
+	m.mergeMethods()
+
+	src, err := addImports(m.apply(), generatedImports(fileMeta, cfg))
+	if err != nil {
+		return "", nil, err
+	}
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", nil, fmt.Errorf("merge factory: format merged code failed, err:%+v", err)
+	}
+	return string(formatted), m.changes, nil
+}
+
+// edit replace src[start:end] with text
+type edit struct {
+	start, end int
+	text       string
+}
+
+type merger struct {
+	src     string
+	fset    *token.FileSet
+	file    *ast.File
+	genSrc  string
+	genFset *token.FileSet
+	genFile *ast.File
+
+	edits   []edit
+	appends []string
+	changes []Change
+}
+
+func (m *merger) offset(pos token.Pos) int {
+	return m.fset.Position(pos).Offset
+}
+
+func (m *merger) genText(node ast.Node) string {
+	return m.genSrc[m.genFset.Position(node.Pos()).Offset:m.genFset.Position(node.End()).Offset]
+}
+
+func (m *merger) mergeFactory(st Struct) {
+	genDecl, genCall := findFactoryDecl(m.genFile, st.Name)
+	if genDecl == nil {
+		return
+	}
+	decl, call := findFactoryDecl(m.file, st.Name)
+	if decl == nil {
+		// the type declaration of factory wrapper is added with the factory
+		if typeDecl := findTypeDecl(m.genFile, st.Name+"Factory"); typeDecl != nil && findTypeDecl(m.file, st.Name+"Factory") == nil {
+			m.appends = append(m.appends, m.genText(typeDecl))
+		}
+		m.appends = append(m.appends, genDeclText(m.genSrc, m.genFset, genDecl))
+		m.changes = append(m.changes, Change{Kind: AddFactory, Factory: st.Name})
+		return
+	}
+	if call == nil {
+		return
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range st.Fields {
+		fields[field.Name] = true
+	}
+	known, hasMarker := parseFieldsMarker(decl)
+	existingAttrs := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		members := attrFields(arg)
+		removed := make([]attrMember, 0, len(members))
+		for _, member := range members {
+			existingAttrs[member.name] = true
+			if !fields[member.name] {
+				removed = append(removed, member)
+				m.changes = append(m.changes, Change{Kind: RemoveAttribute, Factory: st.Name, Name: member.name})
+			}
+		}
+		// the members of attr.Group are removed separately unless the whole group is removed
+		if len(removed) > 0 && len(removed) == len(members) {
+			m.edits = append(m.edits, m.removeArg(arg))
+			continue
+		}
+		for _, member := range removed {
+			m.edits = append(m.edits, m.removeArg(member.arg))
+		}
+	}
+	if !hasMarker {
+		known = existingAttrs
+	}
+	m.removeBuilders(st.Name, known, fields)
+
+	var added strings.Builder
+	for _, arg := range genCall.Args[1:] {
+		members := attrFields(arg)
+		if len(members) != 1 || known[members[0].name] || existingAttrs[members[0].name] {
+			continue
+		}
+		added.WriteString(m.genText(arg) + ",\n")
+		m.changes = append(m.changes, Change{Kind: AddAttribute, Factory: st.Name, Name: members[0].name})
+	}
+	if added.Len() > 0 {
+		rparen := m.offset(call.Rparen)
+		text := added.String()
+		if prev := strings.TrimRight(m.src[:rparen], " \t\n"); !strings.HasSuffix(prev, ",") && !strings.HasSuffix(prev, "(") {
+			text = ",\n" + text
+		}
+		m.edits = append(m.edits, edit{start: rparen, end: rparen, text: text})
+	}
+
+	marker := fieldsMarker(st)
+	if comment := findFieldsMarker(decl); comment != nil {
+		m.edits = append(m.edits, edit{start: m.offset(comment.Pos()), end: m.offset(comment.End()), text: marker})
+	} else {
+		start := m.offset(decl.Pos())
+		m.edits = append(m.edits, edit{start: start, end: start, text: marker + "\n"})
+	}
+}
+
+// removeBuilders remove the typed builder methods of the deleted fields, so the callers fail to compile
+func (m *merger) removeBuilders(name string, known map[string]bool, fields map[string]bool) {
+	knownBuilders, fieldBuilders := make(map[string]bool), make(map[string]bool)
+	for fieldName := range known {
+		knownBuilders[builderName(fieldName)] = true
+	}
+	for fieldName := range fields {
+		fieldBuilders[builderName(fieldName)] = true
+	}
+	for _, decl := range m.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !strings.HasPrefix(funcKey(fn), "*"+name+"Factory.") {
+			continue
+		}
+		method := strings.TrimSuffix(fn.Name.Name, "Gen")
+		if !knownBuilders[method] || fieldBuilders[method] {
+			continue
+		}
+		start, end := fn.Pos(), fn.End()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		m.edits = append(m.edits, edit{start: m.offset(start), end: m.offset(end)})
+		m.changes = append(m.changes, Change{Kind: RemoveMethod, Factory: name, Name: fn.Name.Name})
+	}
+}
+
+// mergeMethods add the association helper methods which are not declared in existing code
+func (m *merger) mergeMethods() {
+	declared := make(map[string]bool)
+	for _, decl := range m.file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			declared[funcKey(fn)] = true
+		}
+	}
+	for _, decl := range m.genFile.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || declared[funcKey(fn)] {
+			continue
+		}
+		m.appends = append(m.appends, m.genText(fn))
+		recv := strings.TrimSuffix(strings.TrimPrefix(funcKey(fn), "*"), "."+fn.Name.Name)
+		m.changes = append(m.changes, Change{Kind: AddMethod, Factory: strings.TrimSuffix(recv, "Factory"), Name: fn.Name.Name})
+	}
+}
+
+// removeArg remove the argument with its trailing comma, the whole lines are removed if the argument occupies them
+func (m *merger) removeArg(arg ast.Expr) edit {
+	start, end := m.offset(arg.Pos()), m.offset(arg.End())
+	if i := strings.IndexFunc(m.src[end:], func(r rune) bool { return r != ' ' && r != '\t' }); i >= 0 && m.src[end+i] == ',' {
+		end += i + 1
+	} else if i := strings.LastIndex(m.src[:start], ","); i >= 0 {
+		// the last argument without trailing comma is removed with the preceding comma
+		return edit{start: i, end: end}
+	}
+	lineStart := strings.LastIndex(m.src[:start], "\n") + 1
+	lineEnd := strings.Index(m.src[end:], "\n")
+	if strings.TrimSpace(m.src[lineStart:start]) == "" && lineEnd >= 0 {
+		rest := strings.TrimSpace(m.src[end : end+lineEnd])
+		if rest == "" || strings.HasPrefix(rest, "//") {
+			return edit{start: lineStart, end: end + lineEnd + 1}
+		}
+	}
+	return edit{start: start, end: end}
+}
+
+// apply apply the edits to existing code and append the new declarations
+func (m *merger) apply() string {
+	sort.Slice(m.edits, func(i, j int) bool { return m.edits[i].start > m.edits[j].start })
+	src := m.src
+	for _, e := range m.edits {
+		src = src[:e.start] + e.text + src[e.end:]
+	}
+	for _, text := range m.appends {
+		src = strings.TrimRight(src, "\n") + "\n\n" + text + "\n"
+	}
+	return src
+}
+
+// findFactoryDecl find the var declaration of factory and its gofactory.New call
+func findFactoryDecl(file *ast.File, name string) (*ast.GenDecl, *ast.CallExpr) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if len(valueSpec.Names) != 1 || valueSpec.Names[0].Name != name || len(valueSpec.Values) != 1 {
+				continue
+			}
+			var call *ast.CallExpr
+			ast.Inspect(valueSpec.Values[0], func(n ast.Node) bool {
+				if c, ok := n.(*ast.CallExpr); ok && call == nil && isSelector(c.Fun, "gofactory", "New") && len(c.Args) > 0 {
+					call = c
+				}
+				return call == nil
+			})
+			return genDecl, call
+		}
+	}
+	return nil, nil
+}
+
+func findTypeDecl(file *ast.File, name string) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if spec.(*ast.TypeSpec).Name.Name == name {
+				return genDecl
+			}
+		}
+	}
+	return nil
+}
+
+// genDeclText get the source of declaration with its doc comments
+func genDeclText(src string, fset *token.FileSet, decl *ast.GenDecl) string {
+	start := decl.Pos()
+	if decl.Doc != nil {
+		start = decl.Doc.Pos()
+	}
+	return src[fset.Position(start).Offset:fset.Position(decl.End()).Offset]
+}
+
+func findFieldsMarker(decl *ast.GenDecl) *ast.Comment {
+	if decl.Doc == nil {
+		return nil
+	}
+	for _, comment := range decl.Doc.List {
+		if strings.HasPrefix(comment.Text, fieldsMarkerPrefix) {
+			return comment
+		}
+	}
+	return nil
+}
+
+func parseFieldsMarker(decl *ast.GenDecl) (map[string]bool, bool) {
+	comment := findFieldsMarker(decl)
+	if comment == nil {
+		return nil, false
+	}
+	known := make(map[string]bool)
+	for _, name := range strings.Split(strings.TrimPrefix(comment.Text, fieldsMarkerPrefix), ",") {
+		known[strings.TrimSpace(name)] = true
+	}
+	return known, true
+}
+
+// attrMember the field set by the attribute and the argument declaring it, the argument is the field spec (e.g
+// "City:city") for the member of attr.Group
+type attrMember struct {
+	name string
+	arg  ast.Expr
+}
+
+// attrFields get the fields set by the attribute argument of factory, the attribute may be wrapped by the attr
+// functions (e.g attr.Unique, attr.Nullable) or the methods of attributer (e.g attr.Str(...).Process(...)), and each
+// member of attr.Group is a field
+func attrFields(arg ast.Expr) []attrMember {
+	call, ok := arg.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "attr" {
+			break
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok || len(inner.Args) == 0 {
+			return nil
+		}
+		call = inner
+	}
+
+	if isSelector(call.Fun, "attr", "Group") {
+		members := make([]attrMember, 0, len(call.Args)-1)
+		for _, memberArg := range call.Args[1:] {
+			spec, ok := stringLit(memberArg)
+			if !ok {
+				continue
+			}
+			name := spec
+			if i := strings.LastIndex(name, ":"); i >= 0 {
+				name = name[:i]
+			}
+			if i := strings.Index(name, "="); i >= 0 {
+				name = name[:i]
+			}
+			members = append(members, attrMember{name: name, arg: memberArg})
+		}
+		return members
+	}
+	// the wrapper takes the wrapped attribute as the first argument (e.g attr.Unique(attr.Str(...), 10))
+	if _, ok := call.Args[0].(*ast.CallExpr); ok {
+		return attrFields(call.Args[0])
+	}
+	name, ok := stringLit(call.Args[0])
+	if !ok {
+		return nil
+	}
+	return []attrMember{{name: name, arg: arg}}
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	return name, err == nil
+}
+
+func isSelector(expr ast.Expr, x, sel string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == x && selector.Sel.Name == sel
+}
+
+// funcKey get the receiver type and name of method (e.g *UserFactory.BelongsToDomain)
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recvType := fn.Recv.List[0].Type
+	prefix := ""
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+		prefix = "*"
+	}
+	if ident, ok := recvType.(*ast.Ident); ok {
+		return prefix + ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
@@ -0,0 +1,164 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeTemplate(t *testing.T) {
+	fm := FileMeta{Package: "model", Structs: []Struct{{
+		Name: "User",
+		Fields: []Field{
+			{Name: "ID", Type: "int64"},
+			{Name: "Name", Type: "string"},
+			{Name: "Nickname", Type: "string"},
+			{Name: "Email", Type: "string"},
+		},
+	}}}
+	generated, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, generated, "//factorygen:fields ID,Name,Nickname,Email\nvar User = ")
+
+	existing := strings.Replace(generated, `attr.Str("Name", genutil.RandAlph(10)),`, `attr.Str("Name", genutil.FixStr("bob")), // keep it`, 1)
+	existing = strings.Replace(existing, "\tattr.Str(\"Email\", genutil.RandEmail()),\n", "", 1)
+	existing += `
+func (f *UserFactory) Admin() *UserFactory {
+	return &UserFactory{f.Attrs(attr.Str("Name", genutil.FixStr("admin")))}
+}
+`
+
+	fm.Structs[0].Fields = []Field{
+		{Name: "ID", Type: "int64"},
+		{Name: "Name", Type: "string"},
+		{Name: "Email", Type: "string"},
+		{Name: "CreatedAt", Type: "Time"},
+		{Name: "ArchivedAt", Type: "NullTime"},
+	}
+	fm.Structs = append(fm.Structs, Struct{Name: "Category", Fields: []Field{{Name: "ID", Type: "int64"}}})
+	merged, changes, err := MergeTemplate(existing, fm, Config{})
+	require.NoError(t, err)
+
+	assert.Contains(t, merged, `attr.Str("Name", genutil.FixStr("bob")), // keep it`)
+	assert.NotContains(t, merged, `"Nickname"`)
+	assert.NotContains(t, merged, `attr.Str("Email", genutil`)
+	assert.Contains(t, merged, `attr.Time("CreatedAt", genutil.Now(time.UTC)),`)
+	assert.Contains(t, merged, `"time"`)
+	assert.Contains(t, merged, `attr.Nullable(attr.Time("ArchivedAt", genutil.Now(time.UTC)), 0.1),`)
+	assert.Contains(t, merged, "//factorygen:fields ID,Name,Email,CreatedAt,ArchivedAt\nvar User = ")
+	assert.Contains(t, merged, "func (f *UserFactory) Admin() *UserFactory {")
+	assert.Contains(t, merged, "type CategoryFactory struct {")
+	assert.Contains(t, merged, "//factorygen:fields ID\nvar Category = ")
+	assert.Equal(t, []Change{
+		{Kind: RemoveAttribute, Factory: "User", Name: "Nickname"},
+		{Kind: RemoveMethod, Factory: "User", Name: "Nickname"},
+		{Kind: RemoveMethod, Factory: "User", Name: "NicknameGen"},
+		{Kind: AddAttribute, Factory: "User", Name: "CreatedAt"},
+		{Kind: AddAttribute, Factory: "User", Name: "ArchivedAt"},
+		{Kind: AddFactory, Factory: "Category"},
+		{Kind: AddMethod, Factory: "User", Name: "CreatedAt"},
+		{Kind: AddMethod, Factory: "User", Name: "CreatedAtGen"},
+		{Kind: AddMethod, Factory: "User", Name: "ArchivedAt"},
+		{Kind: AddMethod, Factory: "User", Name: "ArchivedAtGen"},
+		{Kind: AddMethod, Factory: "Category", Name: "ID"},
+		{Kind: AddMethod, Factory: "Category", Name: "IDGen"},
+	}, changes)
+
+	again, changes, err := MergeTemplate(merged, fm, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, merged, again)
+	assert.Empty(t, changes)
+}
+
+func TestMergeTemplateWithoutMarker(t *testing.T) {
+	existing := `package factory
+
+var User = &UserFactory{gofactory.New(&model.User{}, attr.Int("ID", genutil.SeqInt(1, 1)), attr.Str("Nickname", genutil.RandAlph(3)))}
+`
+	fm := FileMeta{Package: "model", Structs: []Struct{{
+		Name: "User",
+		Fields: []Field{
+			{Name: "ID", Type: "int64"},
+			{Name: "Name", Type: "string"},
+		},
+	}}}
+	merged, changes, err := MergeTemplate(existing, fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, merged, `gofactory.New(&model.User{}, attr.Int("ID", genutil.SeqInt(1, 1)),
+	attr.Str("Name", genutil.RandAlph(10)),
+)`)
+	assert.Contains(t, merged, "//factorygen:fields ID,Name\nvar User = ")
+	assert.Equal(t, Change{Kind: RemoveAttribute, Factory: "User", Name: "Nickname"}, changes[0])
+	assert.Equal(t, Change{Kind: AddAttribute, Factory: "User", Name: "Name"}, changes[1])
+}
+
+func TestMergeTemplateWrappedAttributes(t *testing.T) {
+	existing := `package factory
+
+//factorygen:fields ID,Code,Nickname,City,Zip,Street
+var User = &UserFactory{gofactory.New(
+	&model.User{},
+	attr.Int("ID", genutil.SeqInt(1, 1)),
+	attr.Unique(attr.Str("Code", genutil.RandAlph(4)), 10),
+	attr.Nullable(attr.Unique(attr.Str("Nickname", genutil.RandAlph(5))), 0.5),
+	attr.Group(genutil.Address(), "City:city", "Zip=PostCode:zip"),
+	attr.Unique(attr.Group(genutil.Address(), "Street")),
+)}
+`
+	fm := FileMeta{Package: "model", Structs: []Struct{{
+		Name: "User",
+		Fields: []Field{
+			{Name: "ID", Type: "int64"},
+			{Name: "City", Type: "string"},
+		},
+	}}}
+	merged, changes, err := MergeTemplate(existing, fm, Config{})
+	require.NoError(t, err)
+	assert.NotContains(t, merged, `"Code"`)
+	assert.NotContains(t, merged, `"Nickname"`)
+	assert.NotContains(t, merged, `"Street"`)
+	assert.NotContains(t, merged, `Zip`)
+	assert.Contains(t, merged, `attr.Group(genutil.Address(), "City:city"),`)
+	assert.Equal(t, []Change{
+		{Kind: RemoveAttribute, Factory: "User", Name: "Code"},
+		{Kind: RemoveAttribute, Factory: "User", Name: "Nickname"},
+		{Kind: RemoveAttribute, Factory: "User", Name: "Zip"},
+		{Kind: RemoveAttribute, Factory: "User", Name: "Street"},
+	}, changes[:4])
+
+	again, changes, err := MergeTemplate(merged, fm, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, merged, again)
+	assert.Empty(t, changes)
+}
+
+func TestMergeTemplateEmbeddedFields(t *testing.T) {
+	fileMeta, err := ParsePackage("testdata/embedded")
+	require.NoError(t, err)
+	typesPkg, err := LoadPackage("testdata/embedded")
+	require.NoError(t, err)
+	fileMeta.ResolveTypes(typesPkg)
+	fileMeta.ImportPath = "github.com/vx416/gogo-factory/codegen/testdata/embedded"
+
+	generated, err := GetTempalte(fileMeta, Config{})
+	require.NoError(t, err)
+	merged, changes, err := MergeTemplate(generated, fileMeta, Config{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, generated, merged)
+	assert.Contains(t, merged, `attr.Str("Author.Name", genutil.RandAlph(10), "author_name"),`)
+	assert.Contains(t, merged, `attr.Str("Author.Email", genutil.RandEmail(), "author_email"),`)
+	assert.Contains(t, merged, `attr.Int("Meta.Views", genutil.SeqInt(1, 1), "views"),`)
+
+	blog := &fileMeta.Structs[0]
+	blog.Fields = blog.Fields[:len(blog.Fields)-1]
+	merged, changes, err = MergeTemplate(generated, fileMeta, Config{})
+	require.NoError(t, err)
+	assert.NotContains(t, merged, `"Meta.Views"`)
+	assert.Contains(t, merged, `attr.Str("Author.Name", genutil.RandAlph(10), "author_name"),`)
+	assert.Contains(t, merged, `attr.Str("Author.Email", genutil.RandEmail(), "author_email"),`)
+	assert.Contains(t, changes, Change{Kind: RemoveAttribute, Factory: "Blog", Name: "Meta.Views"})
+	typeCheck(t, merged)
+}
@@ -0,0 +1,125 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SkipGen the generator of rule which skips the field
+const SkipGen = "-"
+
+// Rule choose the generator of fields by field name or column name
+type Rule struct {
+	// Field the glob pattern matched with field name (e.g *Email), it is case sensitive
+	Field string `json:"field" yaml:"field"`
+	// Column the glob pattern matched with column name (e.g *_email)
+	Column string `json:"column" yaml:"column"`
+	// Attr the attr constructor the rule applies to (e.g Str), the rule applies to all attributes if empty
+	Attr string `json:"attr" yaml:"attr"`
+	// Gen the generator expression (e.g genutil.RandEmail()), the field is skipped if it is SkipGen
+	Gen string `json:"gen" yaml:"gen"`
+}
+
+// Rules the rules of choosing generators and the imports required by generators
+type Rules struct {
+	// Imports the packages referenced by generators, the key is the package name
+	Imports map[string]string `json:"imports" yaml:"imports"`
+	Rules   []Rule            `json:"rules" yaml:"rules"`
+}
+
+// DefaultRules the heuristics used to choose generators by field names, the rules loaded by LoadRules take precedence
+var DefaultRules = []Rule{
+	{Field: "ID", Attr: "Int", Gen: "genutil.SeqInt(1, 1)"},
+	{Field: "ID", Attr: "Uint", Gen: "genutil.SeqUint(1, 1)"},
+	{Field: "ID", Attr: "Str", Gen: "genutil.RandUUID()"},
+	{Field: "*ID", Attr: "Str", Gen: "genutil.RandUUID()"},
+	{Column: "*_id", Attr: "Str", Gen: "genutil.RandUUID()"},
+	{Field: "*UUID*", Attr: "Str", Gen: "genutil.RandUUID()"},
+	{Field: "*ID", Attr: "Int", Gen: SkipGen},
+	{Field: "*ID", Attr: "Uint", Gen: SkipGen},
+	{Column: "*_id", Attr: "Int", Gen: SkipGen},
+	{Column: "*_id", Attr: "Uint", Gen: SkipGen},
+	{Field: "*Email*", Attr: "Str", Gen: "genutil.RandEmail()"},
+	{Column: "*email*", Attr: "Str", Gen: "genutil.RandEmail()"},
+	{Field: "*Phone*", Attr: "Str", Gen: `genutil.RandPhoneE164("")`},
+	{Field: "*Mobile*", Attr: "Str", Gen: `genutil.RandPhoneE164("")`},
+	{Column: "*phone*", Attr: "Str", Gen: `genutil.RandPhoneE164("")`},
+	{Field: "*Address*", Attr: "Str", Gen: `genutil.RandStreetAddress("")`},
+	{Column: "*address*", Attr: "Str", Gen: `genutil.RandStreetAddress("")`},
+	{Field: "*URL*", Attr: "Str", Gen: "genutil.RandURL()"},
+	{Field: "*Url*", Attr: "Str", Gen: "genutil.RandURL()"},
+	{Field: "*Website*", Attr: "Str", Gen: "genutil.RandURL()"},
+	{Column: "*url*", Attr: "Str", Gen: "genutil.RandURL()"},
+	{Field: "*City*", Attr: "Str", Gen: `genutil.RandCity("")`},
+	{Field: "*Country*", Attr: "Str", Gen: "genutil.RandCountry()"},
+	{Field: "*Company*", Attr: "Str", Gen: `genutil.RandCompany("")`},
+	{Field: "*PostalCode*", Attr: "Str", Gen: `genutil.RandPostalCode("")`},
+	{Field: "*ZipCode*", Attr: "Str", Gen: `genutil.RandPostalCode("")`},
+	{Field: "Username", Attr: "Str", Gen: "genutil.RandUsername()"},
+	{Field: "UserName", Attr: "Str", Gen: "genutil.RandUsername()"},
+	{Field: "CreatedAt", Attr: "Time", Gen: "genutil.Now(time.UTC)"},
+	{Field: "UpdatedAt", Attr: "Time", Gen: "genutil.Now(time.UTC)"},
+	{Field: "DeletedAt", Gen: SkipGen},
+	{Column: "deleted_at", Gen: SkipGen},
+}
+
+// LoadRules load the rules from yaml or json file, the file is decoded as json if its extension is .json
+func LoadRules(filePath string) (Rules, error) {
+	var rules Rules
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return rules, err
+	}
+	if strings.ToLower(filepath.Ext(filePath)) == ".json" {
+		err = json.Unmarshal(data, &rules)
+	} else {
+		err = yaml.Unmarshal(data, &rules)
+	}
+	if err != nil {
+		return rules, fmt.Errorf("load rules: decode %s failed, err:%+v", filePath, err)
+	}
+
+	for i, rule := range rules.Rules {
+		if rule.Field == "" && rule.Column == "" {
+			return rules, fmt.Errorf("load rules: rule(%d) should set field or column", i)
+		}
+		if rule.Gen == "" {
+			return rules, fmt.Errorf("load rules: rule(%d) should set gen", i)
+		}
+	}
+	return rules, nil
+}
+
+// match check whether the rule applies to the field with the given attr constructor and column
+func (rule Rule) match(field Field, attrName string, column string) bool {
+	if rule.Attr != "" && rule.Attr != attrName {
+		return false
+	}
+	if rule.Field != "" {
+		if ok, _ := path.Match(rule.Field, field.Name); ok {
+			return true
+		}
+	}
+	if rule.Column != "" && column != "" {
+		if ok, _ := path.Match(rule.Column, strings.ToLower(column)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// findRule find the first rule matched with the field
+func findRule(rules []Rule, field Field, attrName string, column string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.match(field, attrName, column) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,92 @@
+package codegen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRules(t *testing.T) {
+	fm := FileMeta{Structs: []Struct{{
+		Name: "Account",
+		Fields: []Field{
+			{Name: "ID", Type: "int64"},
+			{Name: "OwnerID", Type: "int64"},
+			{Name: "UID", Type: "string"},
+			{Name: "Email", Type: "string"},
+			{Name: "Contact", Type: "string", Tag: `db:"contact_phone"`},
+			{Name: "HomeAddress", Type: "string"},
+			{Name: "AvatarURL", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "DeletedAt", Type: "NullTime"},
+		},
+	}}}
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Int("ID", genutil.SeqInt(1, 1)),`)
+	assert.NotContains(t, res, `attr.Int("OwnerID", genutil`)
+	assert.Contains(t, res, `attr.Str("UID", genutil.RandUUID()),`)
+	assert.Contains(t, res, `attr.Str("Email", genutil.RandEmail()),`)
+	assert.Contains(t, res, `attr.Str("Contact", genutil.RandPhoneE164(""), "contact_phone"),`)
+	assert.Contains(t, res, `attr.Str("HomeAddress", genutil.RandStreetAddress("")),`)
+	assert.Contains(t, res, `attr.Str("AvatarURL", genutil.RandURL()),`)
+	assert.Contains(t, res, `attr.Str("Name", genutil.RandAlph(10)),`)
+	assert.NotContains(t, res, `attr.Time("DeletedAt", genutil`)
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	yamlFile := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, ioutil.WriteFile(yamlFile, []byte(`
+imports:
+  randomdata: github.com/Pallinder/go-randomdata
+rules:
+  - field: "Name"
+    attr: Str
+    gen: genutil.RandName(2)
+  - column: "*_email"
+    gen: "-"
+  - field: "OwnerID"
+    gen: genutil.FixInt(1)
+  - field: "Nickname"
+    gen: "func() string { return randomdata.SillyName() }"
+`), 0644))
+	rules, err := LoadRules(yamlFile)
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 4)
+
+	jsonFile := filepath.Join(dir, "rules.json")
+	require.NoError(t, ioutil.WriteFile(jsonFile, []byte(`{"rules": [{"field": "Name", "gen": "genutil.RandName(2)"}]}`), 0644))
+	jsonRules, err := LoadRules(jsonFile)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{Field: "Name", Gen: "genutil.RandName(2)"}}, jsonRules.Rules)
+
+	require.NoError(t, ioutil.WriteFile(jsonFile, []byte(`{"rules": [{"gen": "genutil.RandName(2)"}]}`), 0644))
+	_, err = LoadRules(jsonFile)
+	assert.Error(t, err)
+
+	fm := FileMeta{Structs: []Struct{{
+		Name: "Account",
+		Fields: []Field{
+			{Name: "OwnerID", Type: "int"},
+			{Name: "Name", Type: "string"},
+			{Name: "Nickname", Type: "string"},
+			{Name: "Email", Type: "string", Tag: `db:"work_email"`},
+		},
+	}}}
+	res, err := GetTempalte(fm, Config{Rules: rules})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Int("OwnerID", genutil.FixInt(1)),`)
+	assert.Contains(t, res, `attr.Str("Name", genutil.RandName(2)),`)
+	assert.Contains(t, res, `attr.Str("Nickname", func() string { return randomdata.SillyName() }),`)
+	assert.Contains(t, res, `"github.com/Pallinder/go-randomdata"`)
+	assert.NotContains(t, res, `attr.Str("Email", genutil`)
+}
@@ -0,0 +1,11 @@
+package annotated
+
+//factory:gen
+type Author struct {
+	ID    int64
+	Books []*Book
+}
+
+type Draft struct {
+	ID int64 `db:"id"`
+}
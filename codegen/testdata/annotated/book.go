@@ -0,0 +1,10 @@
+package annotated
+
+// Book the book written by author
+//
+//factory:gen
+type Book struct {
+	ID       int64
+	AuthorID int64
+	Author   *Author
+}
@@ -0,0 +1,38 @@
+package embedded
+
+import (
+	"time"
+
+	"github.com/vx416/gogo-factory/codegen/testdata/annotated"
+)
+
+// BaseModel the columns shared by models
+type BaseModel struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Contact the contact embedded with column prefix
+type Contact struct {
+	Name  string
+	Email string `gorm:"column:email"`
+}
+
+//factory:gen
+type Blog struct {
+	BaseModel
+	Title  string  `db:"title"`
+	Author Contact `gorm:"embedded;embeddedPrefix:author_"`
+	Meta   struct {
+		Views int `db:"views"`
+	}
+}
+
+//factory:gen
+type Post struct {
+	*BaseModel
+	annotated.Draft
+	ID    int64  `db:"post_id"`
+	Title string `db:"title"`
+}
@@ -0,0 +1,37 @@
+package models
+
+type Domain struct {
+	ID          int64
+	Name        string
+	Specialties []*Specialty
+}
+
+type Specialty struct {
+	ID       int64
+	DomainID int64 `db:"domain_id"`
+	Domain   *Domain
+	Owner    *Employee `gorm:"-"`
+}
+
+type Employee struct {
+	ID       int64
+	Profile  *Profile
+	Projects []*Project `gorm:"many2many:employees_projects"`
+}
+
+type Profile struct {
+	ID         int64
+	EmployeeID int64
+}
+
+type Project struct {
+	ID        int64
+	Employees []*Employee
+}
+
+type Category struct {
+	ID       int64
+	ParentID int64
+	Parent   *Category
+	Children []*Category
+}
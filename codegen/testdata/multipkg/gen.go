@@ -0,0 +1,10 @@
+//go:build ignore
+
+package main
+
+// Account the type of ignored file, it should not be loaded with the package
+type Account struct {
+	Code string
+}
+
+func main() {}
@@ -0,0 +1,7 @@
+package multipkg
+
+// Account the model declared with the generator and external tests in the same directory
+type Account struct {
+	ID   int64
+	Name string
+}
@@ -0,0 +1,6 @@
+package multipkg_test
+
+// Account the type of external test package, it should not be loaded with the package
+type Account struct {
+	Code string
+}
@@ -0,0 +1,330 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TypeInfo the type information of field resolved by go/types
+type TypeInfo struct {
+	// AttrName the name of attr constructor (e.g Int, Str), it is empty if the type is unsupported
+	AttrName string
+	// TypeName the type name qualified by package name (e.g decimal.Decimal, *model.Gender)
+	TypeName string
+	// Pointer the field is a pointer
+	Pointer bool
+	// Scanner the field implements sql.Scanner
+	Scanner bool
+	// Decimal the field is a decimal type which is scanned from string, it is generated by attr.Decimal
+	Decimal bool
+	// Precision the total digits of the decimal column (e.g 10 of NUMERIC(10, 2)), it is zero if unknown
+	Precision int
+	// Scale the digits after the decimal point of the decimal column (e.g 2 of NUMERIC(10, 2))
+	Scale int
+	// Nullable the field can be NULL, e.g pointers and the scanners with Valid field (sql.NullString, null.String)
+	Nullable bool
+	// Enums the constants declared with the named type of field in the same package
+	Enums []string
+	// Imports the packages referenced by TypeName except the package of model, the key is the package name
+	Imports map[string]string
+}
+
+var (
+	// sourceMu guard the importer shared by LoadPackage
+	sourceMu sync.Mutex
+	// sourceFset the file set of the packages loaded by LoadPackage and sourceImporter
+	sourceFset = token.NewFileSet()
+	// sourceImporter the importer type checking the imported packages from source, the imported packages are cached
+	// by the importer, so they are type checked once for all loaded packages
+	sourceImporter = importer.ForCompiler(sourceFset, "source", nil)
+)
+
+// LoadPackage parse and type check the go package in dir, the go files are selected by go/build (the test files and
+// the files excluded by build constraints are ignored), the type errors are tolerated so that the package can be
+// loaded partially
+func LoadPackage(dir string) (*types.Package, error) {
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("load package: %+v", err)
+	}
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	files := make([]*ast.File, 0, len(buildPkg.GoFiles)+len(buildPkg.CgoFiles))
+	for _, name := range append(buildPkg.GoFiles, buildPkg.CgoFiles...) {
+		file, err := parser.ParseFile(sourceFset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	conf := types.Config{
+		Importer: sourceImporter,
+		Error:    func(err error) {},
+	}
+	pkg, _ := conf.Check(buildPkg.Name, sourceFset, files, nil)
+	if pkg == nil {
+		return nil, fmt.Errorf("load package: type check %s failed", dir)
+	}
+	return pkg, nil
+}
+
+// ResolveTypes resolve the type information of fields by the type checked package, the fields are resolved by the
+// path of nested fields (e.g Author.Name) or the promoted names, and the embedded structs declared in other packages
+// (e.g gorm.Model) are flattened into their exported fields
+func (fileMeta *FileMeta) ResolveTypes(pkg *types.Package) {
+	for i := range fileMeta.Structs {
+		st := &fileMeta.Structs[i]
+		obj := pkg.Scope().Lookup(st.Name)
+		if obj == nil {
+			continue
+		}
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			continue
+		}
+
+		outer := make(map[string]bool)
+		for _, field := range st.Fields {
+			if !field.Embedded {
+				outer[field.Name] = true
+			}
+		}
+		fields := make([]Field, 0, len(st.Fields))
+		for _, field := range st.Fields {
+			fieldType, ok := lookupFieldType(obj.Type(), field.Name, pkg)
+			if !ok {
+				fields = append(fields, field)
+				continue
+			}
+			if field.Embedded {
+				if embedded, ok := underlyingStruct(fieldType); ok {
+					path := field.Name[:strings.LastIndex(field.Name, ".")+1]
+					prefix := field.Prefix + gormSettings(field.Tag)["EMBEDDEDPREFIX"]
+					promoted := structFields(embedded, path, prefix, pkg)
+					for _, nested := range promoted {
+						if !outer[nested.Name] {
+							outer[nested.Name] = true
+							fields = append(fields, nested)
+						}
+					}
+					if len(promoted) > 0 {
+						continue
+					}
+				}
+			}
+			field.TypeInfo = resolveType(fieldType, pkg)
+			fields = append(fields, field)
+		}
+		st.Fields = fields
+	}
+}
+
+// lookupFieldType get the type of field by the path of nested fields or the promoted name
+func lookupFieldType(t types.Type, name string, pkg *types.Package) (types.Type, bool) {
+	for _, part := range strings.Split(name, ".") {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		obj, _, _ := types.LookupFieldOrMethod(t, false, pkg, part)
+		v, ok := obj.(*types.Var)
+		if !ok {
+			return nil, false
+		}
+		t = v.Type()
+	}
+	return t, true
+}
+
+func underlyingStruct(t types.Type) (*types.Struct, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	return st, ok
+}
+
+// structFields get the exported fields of the embedded struct declared in other package, the nested embedded structs
+// and the structs tagged by gorm embedded are flattened as flattenFields
+func structFields(st *types.Struct, path string, prefix string, pkg *types.Package) []Field {
+	fields := make([]Field, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v, tag := st.Field(i), st.Tag(i)
+		if !v.Exported() {
+			continue
+		}
+		settings := gormSettings(tag)
+		if nested, ok := underlyingStruct(v.Type()); ok {
+			_, gormEmbedded := settings["EMBEDDED"]
+			var nestedFields []Field
+			if v.Embedded() {
+				nestedFields = structFields(nested, path, prefix+settings["EMBEDDEDPREFIX"], pkg)
+			} else if gormEmbedded {
+				nestedFields = structFields(nested, path+v.Name()+".", prefix+settings["EMBEDDEDPREFIX"], pkg)
+			}
+			if len(nestedFields) > 0 {
+				fields = append(fields, nestedFields...)
+				continue
+			}
+		}
+		fields = append(fields, Field{
+			Name:     path + v.Name(),
+			Type:     types.TypeString(v.Type(), func(p *types.Package) string { return p.Name() }),
+			Tag:      tag,
+			Prefix:   prefix,
+			TypeInfo: resolveType(v.Type(), pkg),
+		})
+	}
+	return fields
+}
+
+func resolveType(t types.Type, pkg *types.Package) *TypeInfo {
+	info := &TypeInfo{Imports: make(map[string]string)}
+	info.TypeName = types.TypeString(t, func(p *types.Package) string {
+		if p != pkg {
+			info.Imports[p.Name()] = p.Path()
+		}
+		return p.Name()
+	})
+	if ptr, ok := t.(*types.Pointer); ok {
+		info.Pointer = true
+		info.Nullable = true
+		t = ptr.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+		case "time.Time":
+			info.AttrName = "Time"
+			return info
+		case "github.com/shopspring/decimal.Decimal":
+			info.AttrName = "Decimal"
+			info.Scanner = true
+			info.Decimal = true
+			return info
+		}
+	}
+
+	if isScanner(t) {
+		info.Scanner = true
+		info.Nullable = info.Nullable || hasValidField(t)
+		valueInfo := resolveScannedType(t, pkg)
+		info.AttrName = valueInfo.AttrName
+		info.Decimal = valueInfo.Decimal
+		if info.AttrName == "" {
+			info.AttrName = "Str"
+		}
+		return info
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		info.AttrName = basicAttrName(underlying)
+		if named, ok := t.(*types.Named); ok && info.AttrName != "" {
+			info.Enums = findEnums(named, pkg)
+		}
+	case *types.Slice:
+		if basic, ok := underlying.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			info.AttrName = "Bytes"
+		}
+	}
+	return info
+}
+
+// resolveScannedType resolve the type of value field of scanner struct (e.g String of sql.NullString)
+func resolveScannedType(t types.Type, pkg *types.Package) *TypeInfo {
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		if basic, ok := t.Underlying().(*types.Basic); ok {
+			return &TypeInfo{AttrName: basicAttrName(basic)}
+		}
+		return &TypeInfo{}
+	}
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if basic, ok := field.Type().(*types.Basic); ok && basic.Kind() == types.Bool && field.Name() == "Valid" {
+			continue
+		}
+		return resolveType(field.Type(), pkg)
+	}
+	return &TypeInfo{}
+}
+
+// hasValidField check the struct has the Valid bool field marking whether the value is NULL (e.g sql.NullString)
+func hasValidField(t types.Type) bool {
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if basic, ok := field.Type().Underlying().(*types.Basic); ok && basic.Kind() == types.Bool && field.Name() == "Valid" {
+			return true
+		}
+		if embedded, ok := field.Type().(*types.Named); ok && field.Embedded() && hasValidField(embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+func isScanner(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Scan")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 1 && sig.Results().Len() == 1 && sig.Results().At(0).Type().String() == "error"
+}
+
+func basicAttrName(basic *types.Basic) string {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return "Bool"
+	case info&types.IsString != 0:
+		return "Str"
+	case info&types.IsUnsigned != 0:
+		return "Uint"
+	case info&types.IsInteger != 0:
+		return "Int"
+	case info&types.IsFloat != 0:
+		return "Float"
+	default:
+		return ""
+	}
+}
+
+// findEnums find the constants declared with the named type in the package of model
+func findEnums(named *types.Named, pkg *types.Package) []string {
+	if named.Obj().Pkg() != pkg {
+		return nil
+	}
+	consts := make([]*types.Const, 0, 1)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && c.Exported() && types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+	if len(consts) == 0 {
+		return nil
+	}
+	// keep the declaration order of constants
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+	enums := make([]string, len(consts))
+	for i, c := range consts {
+		enums[i] = c.Name()
+	}
+	return enums
+}
@@ -0,0 +1,88 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTypes(t *testing.T) {
+	node, err := ParseFile("./user.go")
+	require.NoError(t, err)
+	fm := ParseFileMeta(node, "User")
+
+	pkg, err := LoadPackage(".")
+	require.NoError(t, err)
+	fm.ResolveTypes(pkg)
+
+	infos := make(map[string]*TypeInfo)
+	for _, field := range fm.Structs[0].Fields {
+		require.NotNil(t, field.TypeInfo, field.Name)
+		infos[field.Name] = field.TypeInfo
+	}
+	assert.Equal(t, "Int", infos["ID"].AttrName)
+	assert.Equal(t, "Int", infos["Gender"].AttrName)
+	assert.Equal(t, []string{"Male", "Female"}, infos["Gender"].Enums)
+	assert.Equal(t, "Str", infos["Phone"].AttrName)
+	assert.Nil(t, infos["Phone"].Enums)
+	assert.Equal(t, "Str", infos["Address"].AttrName)
+	assert.True(t, infos["Address"].Scanner)
+	assert.Equal(t, "Time", infos["CreatedAt"].AttrName)
+	assert.Equal(t, "Time", infos["UpdatedAt"].AttrName)
+	assert.True(t, infos["Price"].Decimal)
+	assert.Equal(t, "Decimal", infos["Price"].AttrName)
+	assert.Equal(t, "Decimal", infos["Amount"].AttrName)
+	assert.True(t, infos["Amount"].Decimal)
+	assert.Equal(t, "Int", infos["Age"].AttrName)
+	assert.True(t, infos["Age"].Pointer)
+	assert.True(t, infos["Age"].Nullable)
+	assert.True(t, infos["Address"].Nullable)
+	assert.False(t, infos["Price"].Nullable)
+	assert.Equal(t, "", infos["Tags"].AttrName)
+	assert.Equal(t, "", infos["Manager"].AttrName)
+	assert.Equal(t, "*codegen.User", infos["Manager"].TypeName)
+
+	res, err := GetTempalte(fm, Config{Package: "codegen"})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Int("Gender", genutil.RandIntSet(int(Male), int(Female)), "gender"),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Decimal("Amount", genutil.RandDecimal(0, 1000, 2), "amount"), 0.1),`)
+	assert.Contains(t, res, `attr.Time("UpdatedAt", genutil.Now(time.UTC), "updated_at"),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Int("Age", genutil.SeqInt(1, 1), "age"), 0.1),`)
+	assert.Contains(t, res, `attr.Nullable(attr.Str("Address", genutil.RandStreetAddress(""), "address"), 0.1),`)
+	assert.Contains(t, res, `	attr.Decimal("Price", genutil.RandDecimal(0, 1000, 2), "price"),`)
+	assert.Contains(t, res, "func (f *UserFactory) PriceGen(gen func() decimal.Decimal) *UserFactory {")
+	assert.NotContains(t, res, `"Tags"`)
+	assert.NotContains(t, res, `"Manager"`)
+}
+
+func TestResolveTypesExternalPackage(t *testing.T) {
+	node, err := ParseFile("../example/gencode/model/product.go")
+	require.NoError(t, err)
+	fm := ParseFileMeta(node, "Product")
+	fm.ImportPath, err = ResolveImportPath("../example/gencode/model")
+	require.NoError(t, err)
+
+	pkg, err := LoadPackage("../example/gencode/model")
+	require.NoError(t, err)
+	fm.ResolveTypes(pkg)
+
+	res, err := GetTempalte(fm, Config{})
+	require.NoError(t, err)
+	assert.Contains(t, res, `attr.Nullable(attr.Str("Discount", genutil.RandAlph(10), "discount"), 0.1),`)
+	assert.Contains(t, res, `attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),`)
+	assert.NotContains(t, res, `"Buyer"`)
+	typeCheck(t, res)
+}
+
+func TestLoadPackageSkipsOtherPackages(t *testing.T) {
+	pkg, err := LoadPackage("testdata/multipkg")
+	require.NoError(t, err)
+	assert.Equal(t, "multipkg", pkg.Name())
+	obj := pkg.Scope().Lookup("Account")
+	require.NotNil(t, obj)
+	assert.Contains(t, obj.Type().Underlying().String(), "Name string")
+
+	_, err = LoadPackage("testdata/missing")
+	assert.Error(t, err)
+}
@@ -17,18 +17,30 @@ const (
 type Phone string
 
 type User struct {
-	ID        int64
-	Name      string
-	Gender    Gender
-	Phone     Phone
-	Address   sql.NullString
-	CreatedAt time.Time
-	UpdatedAt sql.NullTime
-	Price     decimal.Decimal
-	Amount    decimal.NullDecimal
+	ID        int64               `db:"id"`
+	Name      string              `db:"name" json:"user_name"`
+	Gender    Gender              `gorm:"column:gender"`
+	Phone     Phone               `db:"-"`
+	Address   sql.NullString      `db:"address,omitempty"`
+	CreatedAt time.Time           `db:"created_at"`
+	UpdatedAt sql.NullTime        `db:"updated_at"`
+	Price     decimal.Decimal     `db:"price"`
+	Amount    decimal.NullDecimal `db:"amount"`
+	Age       *int                `db:"age"`
+	Tags      []string            `db:"-"`
+	Manager   *User               `db:"-"`
 	Timestamp
 }
 
+func (User) TableName() string {
+	return "app_users"
+}
+
+type Category struct {
+	ID   int64
+	Name string
+}
+
 type Timestamp struct {
 	CreatedAti uint64
 }
@@ -56,3 +56,8 @@ func (job *InsertJob) jobVal() reflect.Value {
 func (job *InsertJob) GetData() interface{} {
 	return job.val.Interface()
 }
+
+// GetColumnValues get the values of columns inserted by the job
+func (job *InsertJob) GetColumnValues() map[string]interface{} {
+	return job.columnValues
+}
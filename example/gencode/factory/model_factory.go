@@ -0,0 +1,156 @@
+package factory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+	gofactory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/example/gencode/model"
+	"github.com/vx416/gogo-factory/genutil"
+	null "gopkg.in/guregu/null.v4"
+)
+
+type ProductFactory struct {
+	*gofactory.Factory
+}
+
+//factorygen:fields UID,Buyer,Price,Quantity,Discount,CreatedAt,DeletedAt
+var Product = &ProductFactory{gofactory.New(
+	&model.Product{},
+	attr.Str("UID", genutil.RandUUID(), "uid"),
+	attr.Decimal("Price", genutil.RandDecimal(0, 1000, 2), "price"),
+	attr.Decimal("Quantity", genutil.RandDecimal(0, 1000, 2), "quantity"),
+	attr.Nullable(attr.Str("Discount", genutil.RandAlph(10), "discount"), 0.1),
+	attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),
+).Table("products")}
+
+func (f *ProductFactory) UID(v string) *ProductFactory {
+	return f.UIDGen(func() string { return v })
+}
+
+func (f *ProductFactory) UIDGen(gen func() string) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("UID", gen, "uid"))}
+}
+
+func (f *ProductFactory) Price(v decimal.Decimal) *ProductFactory {
+	return f.PriceGen(func() decimal.Decimal { return v })
+}
+
+func (f *ProductFactory) PriceGen(gen func() decimal.Decimal) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("Price", gen, "price"))}
+}
+
+func (f *ProductFactory) Quantity(v decimal.Decimal) *ProductFactory {
+	return f.QuantityGen(func() decimal.Decimal { return v })
+}
+
+func (f *ProductFactory) QuantityGen(gen func() decimal.Decimal) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("Quantity", gen, "quantity"))}
+}
+
+func (f *ProductFactory) Discount(v null.String) *ProductFactory {
+	return f.DiscountGen(func() null.String { return v })
+}
+
+func (f *ProductFactory) DiscountGen(gen func() null.String) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("Discount", gen, "discount"))}
+}
+
+func (f *ProductFactory) CreatedAt(v time.Time) *ProductFactory {
+	return f.CreatedAtGen(func() time.Time { return v })
+}
+
+func (f *ProductFactory) CreatedAtGen(gen func() time.Time) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("CreatedAt", gen, "created_at"))}
+}
+
+func (f *ProductFactory) DeletedAt(v sql.NullTime) *ProductFactory {
+	return f.DeletedAtGen(func() sql.NullTime { return v })
+}
+
+func (f *ProductFactory) DeletedAtGen(gen func() sql.NullTime) *ProductFactory {
+	return &ProductFactory{f.Factory.Attrs(attr.Of("DeletedAt", gen, "deleted_at"))}
+}
+
+type UserFactory struct {
+	*gofactory.Factory
+}
+
+//factorygen:fields ID,Name,Gender,Phone,Address,CreatedAt,UpdatedAt,Password
+var User = &UserFactory{gofactory.New(
+	&model.User{},
+	attr.Int("ID", genutil.SeqInt(1, 1), "id"),
+	attr.Str("Name", genutil.RandAlph(10), "name"),
+	attr.Int("Gender", genutil.RandIntSet(int(model.Male), int(model.Female)), "gender"),
+	attr.Str("Phone", genutil.RandPhoneE164(""), "phone"),
+	attr.Nullable(attr.Str("Address", genutil.RandStreetAddress(""), "address"), 0.1),
+	attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),
+	attr.Nullable(attr.Time("UpdatedAt", genutil.Now(time.UTC), "updated_at"), 0.1),
+	attr.Bytes("Password", genutil.FixBytes([]byte("test")), "password"),
+).Table("users")}
+
+func (f *UserFactory) ID(v int64) *UserFactory {
+	return f.IDGen(func() int64 { return v })
+}
+
+func (f *UserFactory) IDGen(gen func() int64) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("ID", gen, "id"))}
+}
+
+func (f *UserFactory) Name(v string) *UserFactory {
+	return f.NameGen(func() string { return v })
+}
+
+func (f *UserFactory) NameGen(gen func() string) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Name", gen, "name"))}
+}
+
+func (f *UserFactory) Gender(v model.Gender) *UserFactory {
+	return f.GenderGen(func() model.Gender { return v })
+}
+
+func (f *UserFactory) GenderGen(gen func() model.Gender) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Gender", gen, "gender"))}
+}
+
+func (f *UserFactory) Phone(v model.Phone) *UserFactory {
+	return f.PhoneGen(func() model.Phone { return v })
+}
+
+func (f *UserFactory) PhoneGen(gen func() model.Phone) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Phone", gen, "phone"))}
+}
+
+func (f *UserFactory) Address(v sql.NullString) *UserFactory {
+	return f.AddressGen(func() sql.NullString { return v })
+}
+
+func (f *UserFactory) AddressGen(gen func() sql.NullString) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Address", gen, "address"))}
+}
+
+func (f *UserFactory) CreatedAt(v time.Time) *UserFactory {
+	return f.CreatedAtGen(func() time.Time { return v })
+}
+
+func (f *UserFactory) CreatedAtGen(gen func() time.Time) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("CreatedAt", gen, "created_at"))}
+}
+
+func (f *UserFactory) UpdatedAt(v sql.NullTime) *UserFactory {
+	return f.UpdatedAtGen(func() sql.NullTime { return v })
+}
+
+func (f *UserFactory) UpdatedAtGen(gen func() sql.NullTime) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("UpdatedAt", gen, "updated_at"))}
+}
+
+func (f *UserFactory) Password(v model.Hash) *UserFactory {
+	return f.PasswordGen(func() model.Hash { return v })
+}
+
+func (f *UserFactory) PasswordGen(gen func() model.Hash) *UserFactory {
+	return &UserFactory{f.Factory.Attrs(attr.Of("Password", gen, "password"))}
+}
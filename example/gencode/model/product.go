@@ -8,12 +8,13 @@ import (
 	"gopkg.in/guregu/null.v4"
 )
 
+//factory:gen
 type Product struct {
-	UID       string
-	Buyer     *User
-	Price     decimal.Decimal
-	Quantity  decimal.Decimal
-	Discount  null.String
-	CreatedAt time.Time
-	DeletedAt sql.NullTime
+	UID       string          `gorm:"column:uid"`
+	Buyer     *User           `gorm:"-"`
+	Price     decimal.Decimal `gorm:"column:price"`
+	Quantity  decimal.Decimal `gorm:"column:quantity"`
+	Discount  null.String     `gorm:"column:discount"`
+	CreatedAt time.Time       `gorm:"column:created_at"`
+	DeletedAt sql.NullTime    `gorm:"column:deleted_at"`
 }
@@ -1,3 +1,5 @@
+//go:generate go run github.com/vx416/gogo-factory/cmd/factorygen -o ../factory
+
 package model
 
 import (
@@ -15,13 +17,14 @@ const (
 type Phone string
 type Hash []byte
 
+//factory:gen
 type User struct {
-	ID        int64
-	Name      string
-	Gender    Gender
-	Phone     Phone
-	Address   sql.NullString
-	CreatedAt time.Time
-	UpdatedAt sql.NullTime
-	Password  Hash
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	Gender    Gender         `db:"gender"`
+	Phone     Phone          `db:"phone"`
+	Address   sql.NullString `db:"address"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt sql.NullTime   `db:"updated_at"`
+	Password  Hash           `db:"password"`
 }
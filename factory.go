@@ -11,7 +11,7 @@ import (
 
 // New construct a factory object
 func New(obj interface{}, attrs ...attr.Attributer) *Factory {
-	objectSetter := ObjectSetter(attrs)
+	objectSetter := ObjectSetter(attr.Expand(attrs...))
 	fieldColumns := objectSetter.buildFieldColumns(obj)
 
 	return &Factory{
@@ -35,6 +35,7 @@ type Factory struct {
 	only            map[string]bool
 	insertJobsQueue *InsertJobsQueue
 	associations    *Associations
+	level           int32
 }
 
 func (f *Factory) Table(tableName string) *Factory {
@@ -48,7 +49,7 @@ func (f *Factory) InsertFunc(fn dbutil.InsertFunc) *Factory {
 }
 
 func (f *Factory) MustBuild() interface{} {
-	object, _, err := f.build(false)
+	object, _, err := f.build(false, 0)
 	if err != nil {
 		panic(err)
 	}
@@ -56,7 +57,7 @@ func (f *Factory) MustBuild() interface{} {
 }
 
 func (f *Factory) Build() (interface{}, error) {
-	object, _, err := f.build(false)
+	object, _, err := f.build(false, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +65,7 @@ func (f *Factory) Build() (interface{}, error) {
 }
 
 func (f *Factory) MustInsert() interface{} {
-	object, _, err := f.build(true)
+	object, _, err := f.build(true, 0)
 	if err != nil {
 		panic(err)
 	}
@@ -75,7 +76,7 @@ func (f *Factory) MustInsert() interface{} {
 }
 
 func (f *Factory) Insert() (interface{}, error) {
-	object, _, err := f.build(true)
+	object, _, err := f.build(true, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +144,21 @@ func (f *Factory) Only(fields ...string) *Factory {
 	return cloned
 }
 
+// ResetUnique forget the values generated by the unique attributers (e.g attr.Unique(attr.Str(...)), the unique
+// attributers wrapped by attr.Nullable and the unique groups) of the factory and the factories cloned from it, e.g the
+// table is truncated between tests
+func (f *Factory) ResetUnique() *Factory {
+	for _, a := range f.setter {
+		if resetter, ok := a.(attr.UniqueResetter); ok {
+			resetter.ResetUnique()
+		}
+	}
+	return f
+}
+
 // Attrs replace object Attributer and return the new factory
 func (f *Factory) Attrs(attrs ...attr.Attributer) *Factory {
+	attrs = attr.Expand(attrs...)
 	cloned := f.Clone()
 	oldAttrsMap := make(map[string]int)
 	for i := range cloned.setter {
@@ -191,6 +205,32 @@ func (f *Factory) ManyToMany(fieldName string, ass *Association, num int32) *Fac
 	return cloned
 }
 
+// SelfRef associate the object with a parent object built by the factory itself (e.g Category.Parent),
+// the chain of parents stops after depth levels
+func (f *Factory) SelfRef(fieldName string, ass *Association, depth int32) *Factory {
+	cloned := f.Clone()
+	ass = ass.FieldName(fieldName).Num(1)
+	ass.assType = BelongsTo
+	ass.factory = nil
+	ass.self = true
+	ass.depth = depth
+	cloned.associations.addBelongsTo(ass)
+	return cloned
+}
+
+// Children associate the object with num children built by the factory itself (e.g Category.Children),
+// the tree of children stops after depth levels
+func (f *Factory) Children(fieldName string, ass *Association, num int32, depth int32) *Factory {
+	cloned := f.Clone()
+	ass = ass.FieldName(fieldName).Num(num)
+	ass.assType = HasMany
+	ass.factory = nil
+	ass.self = true
+	ass.depth = depth
+	cloned.associations.addHasOneOrMany(ass)
+	return cloned
+}
+
 func (f *Factory) ToAssociation() *Association {
 	return &Association{
 		factory: f.Clone(),
@@ -224,6 +264,19 @@ func (f *Factory) Clone() *Factory {
 	}
 }
 
+func (f *Factory) objectType() reflect.Type {
+	return f.initObj().Elem().Type()
+}
+
+func (f *Factory) selfFactory(as *Association) (*Factory, error) {
+	if as.depth <= 0 {
+		return nil, fmt.Errorf("association(self): field(%s), depth should be greater than zero", as.fieldName)
+	}
+	cloned := f.Clone()
+	cloned.associations.withSelf(as.fieldName, as.depth-1)
+	return cloned, nil
+}
+
 func (f *Factory) buildN(n int, insert bool) (interface{}, error) {
 	if n == 0 {
 		return nil, fmt.Errorf("buildN: size(n) cannot be zero")
@@ -231,7 +284,7 @@ func (f *Factory) buildN(n int, insert bool) (interface{}, error) {
 	values := make([]reflect.Value, 0, n)
 	for i := 0; i < n; i++ {
 		cloned := f.Clone()
-		object, _, err := cloned.build(insert)
+		object, _, err := cloned.build(insert, i)
 		if err != nil {
 			return nil, err
 		}
@@ -248,14 +301,14 @@ func (f *Factory) buildN(n int, insert bool) (interface{}, error) {
 	return sliceVal.Interface(), nil
 }
 
-func (f *Factory) build(insert bool, foreignFV ...*foreignFieldValue) (interface{}, *dbutil.InsertJob, error) {
+func (f *Factory) build(insert bool, index int, foreignFV ...*foreignFieldValue) (interface{}, *dbutil.InsertJob, error) {
 	var (
 		val       = f.initObj()
 		err       error
 		insertJob = &dbutil.InsertJob{}
 	)
 
-	err = f.setter.SetupObject(val, f.omits, f.only)
+	err = f.setter.SetupObjectAt(val, index, f.omits, f.only)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -286,6 +339,9 @@ func (f *Factory) build(insert bool, foreignFV ...*foreignFieldValue) (interface
 
 	if insert {
 		colValues := getColumnValues(val, fieldColumns)
+		for _, col := range f.setter.nullColumns(val, fieldColumns, f.omits, f.only) {
+			colValues[col] = nil
+		}
 		for k, v := range belongToValues {
 			colValues[k] = v
 		}
@@ -0,0 +1,65 @@
+package genutil
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+var bigTen = big.NewInt(10)
+
+// FixDecimal generate the fixed decimal of value (e.g "19.99"), it panics if value is not a decimal
+func FixDecimal(value string) func() decimal.Decimal {
+	val := decimal.RequireFromString(value)
+	return func() decimal.Decimal {
+		return val
+	}
+}
+
+// RandDecimal generate the decimal between min and max with scale digits after the decimal point (e.g 12.34 of
+// RandDecimal(0, 100, 2)), the value is inserted as the string without trailing zeros (e.g 12.3 of 12.30)
+func RandDecimal(min, max float64, scale int32) func() decimal.Decimal {
+	lo := decimal.NewFromFloat(min).Shift(scale).Ceil().BigInt()
+	hi := decimal.NewFromFloat(max).Shift(scale).Floor().BigInt()
+	return randUnits(lo, hi, scale)
+}
+
+// RandNumeric generate the non-negative decimal fitting the NUMERIC(precision, scale) column, the value has at most
+// precision-scale integral digits and exactly scale fractional digits
+func RandNumeric(precision, scale int32) func() decimal.Decimal {
+	hi := new(big.Int).Exp(bigTen, big.NewInt(int64(precision)), nil)
+	return randUnits(big.NewInt(0), hi.Sub(hi, big.NewInt(1)), scale)
+}
+
+// randUnits generate the decimal of which coefficient is between lo and hi, the exponent is -scale
+func randUnits(lo, hi *big.Int, scale int32) func() decimal.Decimal {
+	n := new(big.Int).Sub(hi, lo)
+	if n.Sign() < 0 {
+		n.SetInt64(0)
+	}
+	n.Add(n, big.NewInt(1))
+	return func() decimal.Decimal {
+		units := source.BigInt(n)
+		return decimal.NewFromBigInt(units.Add(units, lo), -scale)
+	}
+}
+
+// RandBigInt generate the big integer between min and max, e.g the values out of the range of int64 for NUMERIC(38, 0)
+func RandBigInt(min, max *big.Int) func() *big.Int {
+	n := new(big.Int).Sub(max, min)
+	if n.Sign() < 0 {
+		n.SetInt64(0)
+	}
+	n.Add(n, big.NewInt(1))
+	lo := new(big.Int).Set(min)
+	return func() *big.Int {
+		val := source.BigInt(n)
+		return val.Add(val, lo)
+	}
+}
+
+// RandBigIntDigits generate the non-negative big integer with at most digits digits
+func RandBigIntDigits(digits int) func() *big.Int {
+	max := new(big.Int).Exp(bigTen, big.NewInt(int64(digits)), nil)
+	return RandBigInt(big.NewInt(0), max.Sub(max, big.NewInt(1)))
+}
@@ -0,0 +1,93 @@
+package genutil
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RandNormal generate the float value of normal distribution with mean and standard deviation
+func RandNormal(mean, stddev float64) func() float64 {
+	return func() float64 {
+		return mean + source.NormFloat64()*stddev
+	}
+}
+
+// RandLogNormal generate the float value of log-normal distribution, mu and sigma are the mean and standard deviation
+// of the natural logarithm of the value (e.g the response time or the order amount)
+func RandLogNormal(mu, sigma float64) func() float64 {
+	return func() float64 {
+		return math.Exp(mu + source.NormFloat64()*sigma)
+	}
+}
+
+// RandExp generate the float value of exponential distribution with the rate (e.g the interval between events),
+// the mean of values is 1/rate. The rate must be positive, the non-positive rate is treated as 1
+func RandExp(rate float64) func() float64 {
+	if rate <= 0 {
+		rate = 1
+	}
+	return func() float64 {
+		return source.ExpFloat64() / rate
+	}
+}
+
+// RandZipf generate the int value between 1 and n of Zipf distribution, the probability of k is proportional to
+// 1/k^s (e.g the popularity of products). The n must be at least 1, the smaller n is treated as 1
+func RandZipf(s float64, n int) func() int {
+	if n < 1 {
+		n = 1
+	}
+	cumulative := make([]float64, n)
+	total := 0.0
+	for k := 1; k <= n; k++ {
+		total += 1 / math.Pow(float64(k), s)
+		cumulative[k-1] = total
+	}
+	return func() int {
+		return sort.SearchFloat64s(cumulative, source.Float64()*total) + 1
+	}
+}
+
+// RoundInt round the float value generated by gen to int and clamp it between min and max, e.g
+// RoundInt(RandNormal(30, 10), 18, 65) generates the ages around 30
+func RoundInt(gen func() float64, min, max int) func() int {
+	return func() int {
+		val := int(math.Round(gen()))
+		if val < min {
+			return min
+		}
+		if val > max {
+			return max
+		}
+		return val
+	}
+}
+
+// Weighted generate the key of weights randomly, the probability of key is proportional to its weight, the keys of
+// non-positive weights are never generated (e.g Weighted(map[string]int{"active": 8, "banned": 2}))
+func Weighted[T comparable](weights map[T]int) func() T {
+	keys := make([]T, 0, len(weights))
+	for key, weight := range weights {
+		if weight > 0 {
+			keys = append(keys, key)
+		}
+	}
+	// sort keys so that the generated values are reproducible with Seed
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	cumulative := make([]int, len(keys))
+	total := 0
+	for i, key := range keys {
+		total += weights[key]
+		cumulative[i] = total
+	}
+	return func() T {
+		if total == 0 {
+			var zero T
+			return zero
+		}
+		return keys[sort.SearchInts(cumulative, source.Intn(total)+1)]
+	}
+}
@@ -0,0 +1,304 @@
+package genutil
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Pallinder/go-randomdata"
+)
+
+// RandPhoneE164 generate the E.164 phone number of locale (e.g +14155550123)
+func RandPhoneE164(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return "+" + l.CallingCode + randDigits(l.PhoneDigits, true)
+	}
+}
+
+// RandStreetAddress generate the street address of locale (e.g 42 Main Street, Hauptstraße 42)
+func RandStreetAddress(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return streetAddress(l)
+	}
+}
+
+func streetAddress(l Locale) string {
+	street := ""
+	if len(l.Streets) > 0 {
+		street = randFrom(l.Streets)
+	} else if street = randomdata.StreetForCountry(l.Code); street == "" {
+		street = randomdata.Street()
+	}
+	return fmt.Sprintf(l.AddressFormat, strconv.Itoa(source.Intn(999)+1), street)
+}
+
+// RandCity generate the city of locale
+func RandCity(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return randFrom(l.Cities)
+	}
+}
+
+// RandPostalCode generate the postal code of locale
+func RandPostalCode(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return randFormat(l.PostalFormat)
+	}
+}
+
+// RandCountry generate the full name of country
+func RandCountry() func() string {
+	return func() string {
+		return randomdata.Country(randomdata.FullCountry)
+	}
+}
+
+// RandCompany generate the company name with the suffix of locale (e.g Brave Fox GmbH)
+func RandCompany(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return upperFirst(randomdata.Adjective()) + " " + upperFirst(randomdata.Noun()) + " " + randFrom(l.CompanySuffixes)
+	}
+}
+
+// RandDomain generate the domain name with the top level domain of locale (e.g bravefox.de)
+func RandDomain(locale string) func() string {
+	l := getLocale(locale)
+	return func() string {
+		return strings.ToLower(randomdata.Adjective()+randomdata.Noun()) + "." + l.TLD
+	}
+}
+
+// RandIPv4 generate the IPv4 address
+func RandIPv4() func() string {
+	return func() string {
+		ip := make(net.IP, net.IPv4len)
+		source.Read(ip)
+		return ip.String()
+	}
+}
+
+// RandIPv6 generate the IPv6 address
+func RandIPv6() func() string {
+	return func() string {
+		ip := make(net.IP, net.IPv6len)
+		source.Read(ip)
+		return ip.String()
+	}
+}
+
+// RandMAC generate the unicast MAC address (e.g 02:1a:2b:3c:4d:5e)
+func RandMAC() func() string {
+	return func() string {
+		mac := make(net.HardwareAddr, 6)
+		source.Read(mac)
+		mac[0] &^= 1
+		return mac.String()
+	}
+}
+
+// RandCardNumber generate the credit-card-like number of length with the prefix (e.g 4 of Visa), the last digit is
+// the Luhn check digit. the length is clamped to fit the prefix and the check digit (e.g length 1 with prefix 4 is 2)
+func RandCardNumber(prefix string, length int) func() string {
+	if length < len(prefix)+1 {
+		length = len(prefix) + 1
+	}
+	return func() string {
+		number := prefix + randDigits(length-len(prefix)-1, false)
+		return number + strconv.Itoa(luhnCheckDigit(number))
+	}
+}
+
+// ValidLuhn check the number passes the Luhn check
+func ValidLuhn(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return luhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+}
+
+// luhnCheckDigit get the check digit appended to the digits
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// RandIBAN generate the IBAN of locale with valid check digits (e.g DE89370400440532013000), the IBAN format of DE
+// is used if the locale doesn't use IBAN
+func RandIBAN(locale string) func() string {
+	l := getLocale(locale)
+	if l.IBANFormat == "" {
+		l = Locales["DE"]
+	}
+	return func() string {
+		bban := randFormat(l.IBANFormat)
+		return l.Code + fmt.Sprintf("%02d", 98-ibanMod97(bban+l.Code+"00")) + bban
+	}
+}
+
+// ValidIBAN check the check digits of IBAN
+func ValidIBAN(iban string) bool {
+	iban = strings.ReplaceAll(strings.ToUpper(iban), " ", "")
+	if len(iban) < 5 {
+		return false
+	}
+	for _, r := range iban {
+		if !unicode.IsDigit(r) && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return ibanMod97(iban[4:]+iban[:4]) == 1
+}
+
+// ibanMod97 get the remainder of the number converted from IBAN characters (A is 10, B is 11, ...) divided by 97
+func ibanMod97(s string) int {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return int(new(big.Int).Mod(n, big.NewInt(97)).Int64())
+}
+
+var loremWords = strings.Fields(`lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt
+ut labore et dolore magna aliqua enim ad minim veniam quis nostrud exercitation ullamco laboris nisi aliquip ex ea
+commodo consequat duis aute irure in reprehenderit voluptate velit esse cillum eu fugiat nulla pariatur excepteur sint
+occaecat cupidatat non proident sunt culpa qui officia deserunt mollit anim id est laborum`)
+
+// RandWords generate n lorem ipsum words separated by space
+func RandWords(n int) func() string {
+	return func() string {
+		return randWords(n)
+	}
+}
+
+// RandSentence generate the lorem ipsum sentence of words between minWords and maxWords
+func RandSentence(minWords, maxWords int) func() string {
+	return func() string {
+		return randSentence(minWords, maxWords)
+	}
+}
+
+// RandParagraph generate the lorem ipsum paragraph of sentences between minSentences and maxSentences
+func RandParagraph(minSentences, maxSentences int) func() string {
+	return func() string {
+		sentences := make([]string, randInts(minSentences, maxSentences, 1)[0])
+		for i := range sentences {
+			sentences[i] = randSentence(4, 12)
+		}
+		return strings.Join(sentences, " ")
+	}
+}
+
+func randWords(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = randFrom(loremWords)
+	}
+	return strings.Join(words, " ")
+}
+
+func randSentence(minWords, maxWords int) string {
+	return upperFirst(randWords(randInts(minWords, maxWords, 1)[0])) + "."
+}
+
+// RandUsername generate the username of lower case first name, separator and digits (e.g alice_42)
+func RandUsername() func() string {
+	return func() string {
+		return strings.ToLower(randomdata.FirstName(randGender(randomdata.RandomGender))) + randFrom([]string{"", "_", "."}) + randDigits(source.Intn(3)+1, false)
+	}
+}
+
+// PasswordPolicy the policy of password generated by RandPassword
+type PasswordPolicy struct {
+	Length int
+	// MinUpper, MinLower, MinDigits and MinSymbols the minimal number of each kind of characters
+	MinUpper   int
+	MinLower   int
+	MinDigits  int
+	MinSymbols int
+	// Symbols the symbols used in password, DefaultSymbols is used if it is empty
+	Symbols string
+}
+
+// DefaultSymbols the symbols used by PasswordPolicy
+const DefaultSymbols = "!@#$%^&*-_=+?"
+
+// DefaultPasswordPolicy the policy with 12 characters including upper case, lower case, digit and symbol
+var DefaultPasswordPolicy = PasswordPolicy{Length: 12, MinUpper: 1, MinLower: 1, MinDigits: 1, MinSymbols: 1}
+
+// RandPassword generate the password matching the policy, the length is extended if it is less than the sum of minimums
+func RandPassword(policy PasswordPolicy) func() string {
+	const (
+		upper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+		lower  = "abcdefghijklmnopqrstuvwxyz"
+		digits = "0123456789"
+	)
+	symbols := policy.Symbols
+	if symbols == "" {
+		symbols = DefaultSymbols
+	}
+	length := policy.Length
+	if min := policy.MinUpper + policy.MinLower + policy.MinDigits + policy.MinSymbols; length < min {
+		length = min
+	}
+	all := upper + lower + digits
+	if policy.MinSymbols > 0 {
+		all += symbols
+	}
+
+	return func() string {
+		password := make([]byte, 0, length)
+		for _, kind := range []struct {
+			chars string
+			min   int
+		}{{upper, policy.MinUpper}, {lower, policy.MinLower}, {digits, policy.MinDigits}, {symbols, policy.MinSymbols}} {
+			for i := 0; i < kind.min; i++ {
+				password = append(password, kind.chars[source.Intn(len(kind.chars))])
+			}
+		}
+		for len(password) < length {
+			password = append(password, all[source.Intn(len(all))])
+		}
+		shuffled := make([]byte, len(password))
+		for i, j := range source.Perm(len(password)) {
+			shuffled[i] = password[j]
+		}
+		return string(shuffled)
+	}
+}
+
+func upperFirst(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
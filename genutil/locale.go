@@ -0,0 +1,143 @@
+package genutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Locale the country specific data and formats used by the locale-aware generators
+type Locale struct {
+	// Code the ISO 3166-1 alpha-2 country code
+	Code    string
+	Country string
+	// CallingCode the country calling code of E.164 phone number
+	CallingCode string
+	// PhoneDigits the digits of national significant number of phone number
+	PhoneDigits int
+	Cities      []string
+	// Streets the street names, go-randomdata is used if it is empty
+	Streets []string
+	// AddressFormat the format of street address, %[1]s is the house number and %[2]s is the street name
+	AddressFormat string
+	// PostalFormat the format of postal code, see RandFormat
+	PostalFormat string
+	// IBANFormat the format of basic bank account number (BBAN) of IBAN, see RandFormat
+	IBANFormat      string
+	TLD             string
+	CompanySuffixes []string
+}
+
+// DefaultLocale the locale used if the locale code is empty or unknown
+const DefaultLocale = "US"
+
+// Locales the locales used by the locale-aware generators, the key is the country code, it can be extended by users
+var Locales = map[string]Locale{
+	"US": {
+		Code: "US", Country: "United States", CallingCode: "1", PhoneDigits: 10,
+		Cities:          []string{"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Seattle", "Boston", "Denver"},
+		AddressFormat:   "%[1]s %[2]s",
+		PostalFormat:    "5n",
+		TLD:             "com",
+		CompanySuffixes: []string{"Inc.", "LLC", "Corp.", "Group"},
+	},
+	"GB": {
+		Code: "GB", Country: "United Kingdom", CallingCode: "44", PhoneDigits: 10,
+		Cities:          []string{"London", "Manchester", "Birmingham", "Leeds", "Glasgow", "Bristol", "Edinburgh", "Liverpool"},
+		AddressFormat:   "%[1]s %[2]s",
+		PostalFormat:    "2a1n 1n2a",
+		IBANFormat:      "4a14n",
+		TLD:             "co.uk",
+		CompanySuffixes: []string{"Ltd", "PLC", "LLP"},
+	},
+	"DE": {
+		Code: "DE", Country: "Germany", CallingCode: "49", PhoneDigits: 10,
+		Cities:          []string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt", "Stuttgart", "Düsseldorf", "Leipzig"},
+		Streets:         []string{"Hauptstraße", "Bahnhofstraße", "Schulstraße", "Gartenweg", "Lindenallee", "Bergstraße", "Kirchplatz"},
+		AddressFormat:   "%[2]s %[1]s",
+		PostalFormat:    "5n",
+		IBANFormat:      "18n",
+		TLD:             "de",
+		CompanySuffixes: []string{"GmbH", "AG", "KG"},
+	},
+	"FR": {
+		Code: "FR", Country: "France", CallingCode: "33", PhoneDigits: 9,
+		Cities:          []string{"Paris", "Lyon", "Marseille", "Toulouse", "Nice", "Nantes", "Strasbourg", "Bordeaux"},
+		Streets:         []string{"Rue de la Paix", "Rue Victor Hugo", "Avenue Jean Jaurès", "Boulevard Voltaire", "Rue du Moulin", "Place de la République"},
+		AddressFormat:   "%[1]s %[2]s",
+		PostalFormat:    "5n",
+		IBANFormat:      "10n11c2n",
+		TLD:             "fr",
+		CompanySuffixes: []string{"SA", "SARL", "SAS"},
+	},
+	"JP": {
+		Code: "JP", Country: "Japan", CallingCode: "81", PhoneDigits: 10,
+		Cities:          []string{"Tokyo", "Osaka", "Yokohama", "Nagoya", "Sapporo", "Fukuoka", "Kobe", "Kyoto"},
+		Streets:         []string{"Chuo", "Minato", "Shibuya", "Shinjuku", "Nakano", "Meguro", "Setagaya"},
+		AddressFormat:   "%[1]s %[2]s",
+		PostalFormat:    "3n-4n",
+		TLD:             "jp",
+		CompanySuffixes: []string{"K.K.", "G.K.", "Co., Ltd."},
+	},
+	"TW": {
+		Code: "TW", Country: "Taiwan", CallingCode: "886", PhoneDigits: 9,
+		Cities:          []string{"Taipei", "New Taipei", "Kaohsiung", "Taichung", "Tainan", "Hsinchu", "Keelung", "Taoyuan"},
+		Streets:         []string{"Zhongshan Rd.", "Minsheng Rd.", "Heping E. Rd.", "Zhongxiao E. Rd.", "Xinyi Rd.", "Ren'ai Rd."},
+		AddressFormat:   "No. %[1]s, %[2]s",
+		PostalFormat:    "3n",
+		TLD:             "tw",
+		CompanySuffixes: []string{"Co., Ltd.", "Corp."},
+	},
+}
+
+// getLocale get the locale of country code, DefaultLocale is used if the code is empty or unknown
+func getLocale(code string) Locale {
+	if locale, ok := Locales[strings.ToUpper(code)]; ok {
+		return locale
+	}
+	return Locales[DefaultLocale]
+}
+
+// RandFormat generate the string of format, which is a sequence of counts followed by the kind of characters,
+// n is digit, a is upper case letter and c is upper case letter or digit (e.g 4a14n), the other characters are
+// kept as is (e.g 3n-4n)
+func RandFormat(format string) func() string {
+	return func() string {
+		return randFormat(format)
+	}
+}
+
+func randFormat(format string) string {
+	const (
+		digits  = "0123456789"
+		letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	)
+	var builder strings.Builder
+	count := 0
+	for _, r := range format {
+		if unicode.IsDigit(r) {
+			count = count*10 + int(r-'0')
+			continue
+		}
+		if count == 0 {
+			count = 1
+		}
+		var chars string
+		switch r {
+		case 'n':
+			chars = digits
+		case 'a':
+			chars = letters
+		case 'c':
+			chars = letters + digits
+		default:
+			builder.WriteString(strings.Repeat(string(r), count))
+			count = 0
+			continue
+		}
+		for i := 0; i < count; i++ {
+			builder.WriteByte(chars[source.Intn(len(chars))])
+		}
+		count = 0
+	}
+	return builder.String()
+}
@@ -0,0 +1,66 @@
+package genutil
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Currencies the ISO-4217 currency codes and the digits of their minor units (e.g 2 for USD cents, 0 for JPY)
+var Currencies = map[string]int32{
+	"USD": 2, "EUR": 2, "GBP": 2, "JPY": 0, "CNY": 2, "TWD": 2, "HKD": 2, "KRW": 0, "SGD": 2, "INR": 2,
+	"AUD": 2, "CAD": 2, "CHF": 2, "SEK": 2, "NOK": 2, "DKK": 2, "BRL": 2, "MXN": 2, "ZAR": 2, "KWD": 3,
+	"BHD": 3, "VND": 0,
+}
+
+// currencyScale get the digits of minor unit of the currency, 2 is used for the unknown currencies
+func currencyScale(code string) int32 {
+	if scale, ok := Currencies[code]; ok {
+		return scale
+	}
+	return 2
+}
+
+// currencyCodes get the sorted codes of Currencies if codes is empty
+func currencyCodes(codes []string) []string {
+	if len(codes) > 0 {
+		return codes
+	}
+	codes = make([]string, 0, len(Currencies))
+	for code := range Currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// RandCurrency generate the ISO-4217 currency code chosen from codes randomly (all Currencies if it is empty)
+func RandCurrency(codes ...string) func() string {
+	codes = currencyCodes(codes)
+	return func() string {
+		return randFrom(codes)
+	}
+}
+
+// RandAmount generate the amount of currency between min and max, the scale is the digits of minor unit of currency
+// (e.g 12.30 for USD, 1230 for JPY)
+func RandAmount(min, max float64, currency string) func() decimal.Decimal {
+	return RandDecimal(min, max, currencyScale(currency))
+}
+
+// Money generate the money record of which amount matches the currency chosen from currencies randomly (all
+// Currencies if it is empty). The keys are Amount (decimal.Decimal) and Currency
+func Money(min, max float64, currencies ...string) func() Record {
+	codes := currencyCodes(currencies)
+	amounts := make(map[string]func() decimal.Decimal, len(codes))
+	for _, code := range codes {
+		amounts[code] = RandAmount(min, max, code)
+	}
+	return func() Record {
+		code := randFrom(codes)
+		return Record{
+			"Amount":   amounts[code](),
+			"Currency": code,
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package genutil
 
 import (
+	"strings"
 	"time"
 
 	"github.com/Pallinder/go-randomdata"
@@ -70,7 +71,7 @@ func RandAlphSet(n int, alphs string) func() string {
 
 func RandUUID() func() string {
 	return func() string {
-		return uuid.New().String()
+		return uuid.Must(uuid.NewRandomFromReader(source)).String()
 	}
 }
 
@@ -90,7 +91,7 @@ func RandBool(ratio float64) func() bool {
 
 func RandTime(min, max time.Time) func() time.Time {
 	minUnix := int(min.Unix())
-	maxUnix := int(min.Unix())
+	maxUnix := int(max.Unix())
 	return func() time.Time {
 		timeUnix := int64(randInts(minUnix, maxUnix, 1)[0])
 		return time.Unix(timeUnix, 0)
@@ -99,12 +100,46 @@ func RandTime(min, max time.Time) func() time.Time {
 
 func RandFirstName(gender int) func() string {
 	return func() string {
-		return randomdata.FirstName(gender)
+		return randomdata.FirstName(randGender(gender))
 	}
 }
 
 func RandName(gender int) func() string {
 	return func() string {
-		return randomdata.FirstName(gender) + ", " + randomdata.LastName()
+		return randomdata.FirstName(randGender(gender)) + ", " + randomdata.LastName()
+	}
+}
+
+// emailDomains the domains of emails generated by RandEmail
+var emailDomains = []string{"example.com", "example.org", "example.net", "test.com", "mail.com"}
+
+func RandEmail() func() string {
+	return func() string {
+		name := randomdata.FirstName(randGender(randomdata.RandomGender)) + randomdata.LastName()
+		return strings.ToLower(name) + randDigits(source.Intn(3)+1, false) + "@" + randFrom(emailDomains)
+	}
+}
+
+// RandPhone generate the phone number of go-randomdata
+//
+// Deprecated: use RandPhoneE164, which generates the locale-aware E.164 phone number
+func RandPhone() func() string {
+	return func() string {
+		return randomdata.PhoneNumber()
+	}
+}
+
+// RandAddress generate the address of go-randomdata
+//
+// Deprecated: use RandStreetAddress, which generates the locale-aware street address
+func RandAddress() func() string {
+	return func() string {
+		return randomdata.Address()
+	}
+}
+
+func RandURL() func() string {
+	return func() string {
+		return "https://" + strings.ToLower(randomdata.SillyName()) + ".com/" + strings.ToLower(randomdata.Noun())
 	}
 }
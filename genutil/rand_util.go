@@ -1,35 +1,103 @@
 package genutil
 
 import (
+	"math/big"
 	"math/rand"
+	"sync"
 	"time"
+
+	"github.com/Pallinder/go-randomdata"
 )
 
 var letter = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
+// lockedRand the random source shared by generators, it is safe for concurrent use
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (lr *lockedRand) Intn(n int) int {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Intn(n)
+}
+
+func (lr *lockedRand) Float64() float64 {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Float64()
+}
+
+func (lr *lockedRand) NormFloat64() float64 {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.NormFloat64()
+}
+
+func (lr *lockedRand) ExpFloat64() float64 {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.ExpFloat64()
+}
+
+func (lr *lockedRand) Int63() int64 {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Int63()
+}
+
+func (lr *lockedRand) Read(p []byte) (int, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Read(p)
+}
+
+// BigInt get the random big integer in [0, n)
+func (lr *lockedRand) BigInt(n *big.Int) *big.Int {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return new(big.Int).Rand(lr.r, n)
+}
+
+func (lr *lockedRand) Perm(n int) []int {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Perm(n)
+}
+
+// source the random source of generators, it is seeded by current time unless Seed is called
+var source = &lockedRand{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// Seed seed the random source of generators (including the generators backed by go-randomdata and RandUUID),
+// so the generated values are reproducible with the same seed
+func Seed(seed int64) {
+	source.mu.Lock()
+	source.r = rand.New(rand.NewSource(seed))
+	source.mu.Unlock()
+	randomdata.CustomRand(rand.New(rand.NewSource(seed)))
+}
+
 func randFloats(min, max float64, n int) []float64 {
-	rand.Seed(time.Now().UnixNano())
 	res := make([]float64, n)
 	for i := range res {
-		res[i] = min + rand.Float64()*(max-min)
+		res[i] = min + source.Float64()*(max-min)
 	}
 	return res
 }
 
 func randInts(min, max int, n int) []int {
-	rand.Seed(time.Now().UnixNano())
 	res := make([]int, n)
 	for i := range res {
-		res[i] = rand.Intn(max-min+1) + min
+		res[i] = source.Intn(max-min+1) + min
 	}
 	return res
 }
 
 func randUints(min, max uint, n int) []uint {
-	rand.Seed(time.Now().UnixNano())
 	res := make([]uint, n)
 	for i := range res {
-		res[i] = uint(rand.Intn(int(max)-int(min)+1) + int(min))
+		res[i] = uint(source.Intn(int(max)-int(min)+1) + int(min))
 	}
 	return res
 }
@@ -37,15 +105,41 @@ func randUints(min, max uint, n int) []uint {
 func randString(n int) string {
 	b := make([]rune, n)
 	for i := range b {
-		b[i] = letter[rand.Intn(len(letter))]
+		b[i] = letter[source.Intn(len(letter))]
 	}
 	return string(b)
 }
 
 func randBool(ratio float64) bool {
-	rand.Seed(time.Now().UnixNano())
-	if ratio > rand.Float64() {
+	if ratio > source.Float64() {
 		return true
 	}
 	return false
 }
+
+// randFrom get a random element of the strings
+func randFrom(set []string) string {
+	return set[source.Intn(len(set))]
+}
+
+// randDigits get the random digits of length n, the first digit is not zero if nonZero is true
+func randDigits(n int, nonZero bool) string {
+	b := make([]byte, n)
+	for i := range b {
+		if i == 0 && nonZero {
+			b[i] = byte('1' + source.Intn(9))
+			continue
+		}
+		b[i] = byte('0' + source.Intn(10))
+	}
+	return string(b)
+}
+
+// randGender get the gender of go-randomdata, the random gender is chosen by source since go-randomdata
+// chooses it by the global source of math/rand
+func randGender(gender int) int {
+	if gender != randomdata.Male && gender != randomdata.Female {
+		return source.Intn(2)
+	}
+	return gender
+}
@@ -0,0 +1,64 @@
+package genutil
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+)
+
+// Record the values of correlated fields generated together, the key is the field name of record (e.g City)
+type Record map[string]interface{}
+
+// Address generate the address record of which fields are consistent with the locale, the locale is chosen from the
+// locales randomly (all Locales if it is empty). The keys are Street, City, Zip (PostalCode), Country, CountryCode and
+// Phone
+func Address(locales ...string) func() Record {
+	return func() Record {
+		l := randLocale(locales)
+		zip := randFormat(l.PostalFormat)
+		return Record{
+			"Street":      streetAddress(l),
+			"City":        randFrom(l.Cities),
+			"Zip":         zip,
+			"PostalCode":  zip,
+			"Country":     l.Country,
+			"CountryCode": l.Code,
+			"Phone":       "+" + l.CallingCode + randDigits(l.PhoneDigits, true),
+		}
+	}
+}
+
+// Person generate the person record of which name, email and username are consistent, the locale is chosen from the
+// locales randomly (all Locales if it is empty). The keys are FirstName, LastName, Name, Email, Username, Phone,
+// Country and CountryCode
+func Person(locales ...string) func() Record {
+	return func() Record {
+		l := randLocale(locales)
+		first := randomdata.FirstName(randGender(randomdata.RandomGender))
+		last := randomdata.LastName()
+		login := strings.ToLower(first + "." + last)
+		return Record{
+			"FirstName":   first,
+			"LastName":    last,
+			"Name":        first + " " + last,
+			"Email":       login + "@example." + l.TLD,
+			"Username":    login + randDigits(2, false),
+			"Phone":       "+" + l.CallingCode + randDigits(l.PhoneDigits, true),
+			"Country":     l.Country,
+			"CountryCode": l.Code,
+		}
+	}
+}
+
+// randLocale get the random locale of codes, all Locales are used if codes is empty
+func randLocale(codes []string) Locale {
+	if len(codes) == 0 {
+		codes = make([]string, 0, len(Locales))
+		for code := range Locales {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+	}
+	return getLocale(randFrom(codes))
+}
@@ -0,0 +1,114 @@
+package genutil
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// RegexMaxRepeat the max repeats of unbounded repetitions (e.g *, + and {2,}) of Regex
+const RegexMaxRepeat = 10
+
+// printableRanges the rune ranges preferred by Regex for character classes and any character (.)
+var printableRanges = []rune{' ', '~'}
+
+// Regex generate the string matching the Go regexp/syntax pattern (e.g SKU-[A-Z]{3}-\d{4}), the unbounded repetitions
+// are repeated at most RegexMaxRepeat times more than their min, and the printable ASCII characters are preferred for
+// character classes. It panics if the pattern is invalid
+func Regex(pattern string) func() string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		panic(fmt.Errorf("genutil: parse regex %q, err:%+v", pattern, err))
+	}
+	re = re.Simplify()
+	return func() string {
+		var sb strings.Builder
+		genRegex(&sb, re)
+		return sb.String()
+	}
+}
+
+func genRegex(sb *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && randBool(0.5) {
+				r = unicode.SimpleFold(r)
+			}
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(randRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		sb.WriteRune(randRune(printableRanges))
+	case syntax.OpCapture:
+		genRegex(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			genRegex(sb, sub)
+		}
+	case syntax.OpAlternate:
+		genRegex(sb, re.Sub[source.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < 0 {
+			max = min + RegexMaxRepeat
+		}
+		for n := min + source.Intn(max-min+1); n > 0; n-- {
+			genRegex(sb, re.Sub[0])
+		}
+	}
+	// the empty matches and assertions (e.g ^, $ and \b) generate nothing
+}
+
+// randRune get the random rune of ranges (pairs of lo and hi), the printable ASCII characters of ranges are preferred
+func randRune(ranges []rune) rune {
+	if printable := intersectRanges(ranges, printableRanges); len(printable) > 0 {
+		ranges = printable
+	}
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return 0
+	}
+	n := source.Intn(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
+
+// intersectRanges get the intersection of the sorted rune ranges a and b
+func intersectRanges(a, b []rune) []rune {
+	res := make([]rune, 0)
+	for i := 0; i+1 < len(a); i += 2 {
+		for j := 0; j+1 < len(b); j += 2 {
+			lo, hi := a[i], a[i+1]
+			if b[j] > lo {
+				lo = b[j]
+			}
+			if b[j+1] < hi {
+				hi = b[j+1]
+			}
+			if lo <= hi {
+				res = append(res, lo, hi)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,33 @@
+package genutil
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs the functions can be used in the templates of Template
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// Template generate the string rendered by the text/template with the object under construction for attr.StrFnErr,
+// e.g Template("{{.First}}.{{.Last}}@corp.com") or Template("{{.First | lower}}-{{.ID}}"), the fields of previous
+// attributes are set. The functions lower, upper and trim can be used in the template. Like regexp.MustCompile, it
+// panics if the template is invalid, and the generator returns the error if the object cannot be rendered (e.g unknown
+// field)
+func Template(text string) func(obj interface{}) (string, error) {
+	tmpl, err := template.New("genutil").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		panic(fmt.Errorf("genutil: parse template %q, err:%+v", text, err))
+	}
+	return func(obj interface{}) (string, error) {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, obj); err != nil {
+			return "", fmt.Errorf("genutil: render template %q, err:%+v", text, err)
+		}
+		return sb.String(), nil
+	}
+}
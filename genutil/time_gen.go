@@ -0,0 +1,99 @@
+package genutil
+
+import (
+	"time"
+)
+
+// sampleRetries the retries of sampling the time matching the condition (e.g weekday)
+const sampleRetries = 100
+
+// PastDays generate the time between days ago and now
+func PastDays(days int) func() time.Time {
+	return PastDuration(time.Duration(days) * 24 * time.Hour)
+}
+
+// FutureDays generate the time between now and days later
+func FutureDays(days int) func() time.Time {
+	return FutureDuration(time.Duration(days) * 24 * time.Hour)
+}
+
+// PastDuration generate the time between d ago and now
+func PastDuration(d time.Duration) func() time.Time {
+	return func() time.Time {
+		now := time.Now()
+		return randTimeBetween(now.Add(-d), now)
+	}
+}
+
+// FutureDuration generate the time between now and d later
+func FutureDuration(d time.Duration) func() time.Time {
+	return func() time.Time {
+		now := time.Now()
+		return randTimeBetween(now, now.Add(d))
+	}
+}
+
+// RandWeekday generate the time between min and max on weekdays (Monday to Friday), the time may be on weekends if
+// there is no weekday between min and max
+func RandWeekday(min, max time.Time) func() time.Time {
+	return RandBusinessHours(min, max, 0, 24)
+}
+
+// RandBusinessHours generate the time between min and max on weekdays within business hours [startHour, endHour) in
+// the location of min (e.g RandBusinessHours(min, max, 9, 18)), the time may be outside business hours if there is no
+// business hour between min and max
+func RandBusinessHours(min, max time.Time, startHour, endHour int) func() time.Time {
+	return func() time.Time {
+		var t time.Time
+		for retries := 0; retries < sampleRetries; retries++ {
+			t = randTimeBetween(min, max).In(min.Location())
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, min.Location())
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				continue
+			}
+			start, end := day.Add(time.Duration(startHour)*time.Hour), day.Add(time.Duration(endHour)*time.Hour)
+			if start.Before(min) {
+				start = min
+			}
+			if end.After(max) {
+				end = max
+			}
+			if start.Before(end) {
+				return randTimeBetween(start, end)
+			}
+		}
+		return t
+	}
+}
+
+// MonotonicTime generate the increasing time starting from start, the time is increased by step plus the random
+// jitter between -jitter and jitter (e.g the timestamps of events), the increment is at least 1ns if jitter is
+// greater than step
+func MonotonicTime(start time.Time, step, jitter time.Duration) func() time.Time {
+	t := start
+	first := true
+	return func() time.Time {
+		if first {
+			first = false
+			return t
+		}
+		delta := step
+		if jitter > 0 {
+			delta += time.Duration(source.Int63()%int64(2*jitter+1)) - jitter
+		}
+		if delta <= 0 {
+			delta = time.Nanosecond
+		}
+		t = t.Add(delta)
+		return t
+	}
+}
+
+// randTimeBetween get the random time in [min, max) in the location of min
+func randTimeBetween(min, max time.Time) time.Time {
+	d := max.Sub(min)
+	if d <= 0 {
+		return min
+	}
+	return min.Add(time.Duration(source.Int63() % int64(d)))
+}
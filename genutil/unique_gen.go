@@ -0,0 +1,100 @@
+package genutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultUniqueRetries the retries of unique generators if maxRetries is not positive
+const DefaultUniqueRetries = 100
+
+// ErrUniqueExhausted the error wrapped by the errors of unique generators which cannot generate a new value
+var ErrUniqueExhausted = errors.New("unique value space exhausted")
+
+// UniqueSet the set of issued values of unique generators, it is safe for concurrent use
+type UniqueSet struct {
+	mu         sync.Mutex
+	maxRetries int
+	seen       map[interface{}]struct{}
+}
+
+// NewUniqueSet create the set which retries maxRetries times to get a new value, DefaultUniqueRetries is used if
+// maxRetries is not positive
+func NewUniqueSet(maxRetries int) *UniqueSet {
+	if maxRetries <= 0 {
+		maxRetries = DefaultUniqueRetries
+	}
+	return &UniqueSet{maxRetries: maxRetries, seen: make(map[interface{}]struct{})}
+}
+
+// Next call gen until it gets the value not issued yet and record it, the error wraps ErrUniqueExhausted if all
+// retries get issued values. nil is never recorded since NULL doesn't violate UNIQUE constraints
+func (s *UniqueSet) Next(gen func() (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i <= s.maxRetries; i++ {
+		val, err := gen()
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		key := uniqueKey(val)
+		if _, ok := s.seen[key]; ok {
+			continue
+		}
+		s.seen[key] = struct{}{}
+		return val, nil
+	}
+	return nil, fmt.Errorf("generate unique value: no new value after %d retries (%d values issued), err:%w", s.maxRetries, len(s.seen), ErrUniqueExhausted)
+}
+
+// Len get the number of issued values
+func (s *UniqueSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.seen)
+}
+
+// Reset forget the issued values, e.g start a new scope after the table is truncated
+func (s *UniqueSet) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen = make(map[interface{}]struct{})
+}
+
+// uniqueKey get the comparable key of value, []byte and time.Time are compared by content and instant
+func uniqueKey(val interface{}) interface{} {
+	switch v := val.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UnixNano()
+	}
+	if val != nil && !reflect.TypeOf(val).Comparable() {
+		return fmt.Sprintf("%#v", val)
+	}
+	return val
+}
+
+// Unique wrap the generator to generate the values not generated before by the returned generator, each call of Unique
+// has its own scope. The returned generator returns the error wrapping ErrUniqueExhausted if it cannot get a new value
+// within maxRetries, use it with attr.OfErr to return the error from Build or Insert
+func Unique[T comparable](gen func() T, maxRetries int) func() (T, error) {
+	set := NewUniqueSet(maxRetries)
+	return func() (T, error) {
+		val, err := set.Next(func() (interface{}, error) {
+			return gen(), nil
+		})
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return val.(T), nil
+	}
+}
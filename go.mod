@@ -1,6 +1,6 @@
 module github.com/vx416/gogo-factory
 
-go 1.15
+go 1.18
 
 require (
 	github.com/Pallinder/go-randomdata v1.2.0
@@ -10,9 +10,16 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.4
 	github.com/shopspring/decimal v1.2.0
 	github.com/stretchr/testify v1.6.1
-	golang.org/x/text v0.3.5 // indirect
-	google.golang.org/appengine v1.6.7 // indirect
 	gopkg.in/guregu/null.v4 v4.0.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 	gorm.io/driver/sqlite v1.1.3
 	gorm.io/gorm v1.20.6
 )
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.3.5 // indirect
+	google.golang.org/appengine v1.6.7 // indirect
+)
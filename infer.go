@@ -0,0 +1,167 @@
+package gofactory
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/vx416/gogo-factory/internal/tagutil"
+)
+
+// Infer enable inferring the association keys from gorm tags (foreignKey, references, many2many, polymorphic...),
+// db tags and naming conventions (e.g DomainID/domain_id for field Domain), the keys setup explicitly are kept
+func (as *Association) Infer() *Association {
+	cloned := as.clone()
+	cloned.infer = true
+	return cloned
+}
+
+func (as *Association) inferKeys(parentType reflect.Type, parent *Factory, factory *Factory) error {
+	errMsg := "association(infer): field(%s), "
+
+	associatedType := factory.objectType()
+	parentField, ok := parentType.FieldByName(as.fieldName)
+	if !ok {
+		return fmt.Errorf(errMsg+"field not found in %s", as.fieldName, parentType.Name())
+	}
+	settings := tagutil.ParseGormTag(parentField.Tag.Get("gorm"))
+
+	switch as.assType {
+	case BelongsTo:
+		if len(as.foreignFields) == 0 {
+			as.foreignFields = tagutil.SplitTagValue(settings["FOREIGNKEY"], as.fieldName+"ID")
+		}
+		if len(as.referFields) == 0 {
+			as.referFields = tagutil.SplitTagValue(settings["REFERENCES"], "ID")
+		}
+		if err := requireFields(parentType, as.foreignFields); err != nil {
+			return fmt.Errorf(errMsg+"foreign field %s, please set it explicitly", as.fieldName, err)
+		}
+		if err := requireFields(associatedType, as.referFields); err != nil {
+			return fmt.Errorf(errMsg+"refer field %s, please set it explicitly", as.fieldName, err)
+		}
+		if len(as.foreignKeys) == 0 {
+			as.foreignKeys = fieldColumns(parentType, as.foreignFields)
+		}
+	case HasOne, HasMany:
+		if polymorphicName := settings["POLYMORPHIC"]; polymorphicName != "" && as.polymorphic == nil {
+			typeField, ok := associatedType.FieldByName(polymorphicName + "Type")
+			if !ok {
+				return fmt.Errorf(errMsg+"polymorphic type field %sType not found in %s", as.fieldName, polymorphicName, associatedType.Name())
+			}
+			// the type value is the table of parent as gorm, the table is named by convention if it is not set
+			typeValue := settings["POLYMORPHICVALUE"]
+			if typeValue == "" {
+				typeValue = parent.table
+			}
+			if typeValue == "" {
+				typeValue = tagutil.Pluralize(tagutil.ToSnakeCase(parentType.Name()))
+			}
+			as.polymorphic = &polymorphic{
+				typeField: polymorphicName + "Type",
+				typeCol:   columnName(typeField),
+				typeValue: typeValue,
+			}
+			if len(as.foreignFields) == 0 {
+				as.foreignFields = []string{polymorphicName + "ID"}
+			}
+		}
+		if len(as.foreignFields) == 0 {
+			as.foreignFields = tagutil.SplitTagValue(settings["FOREIGNKEY"], parentType.Name()+"ID")
+		}
+		if len(as.referFields) == 0 {
+			as.referFields = tagutil.SplitTagValue(settings["REFERENCES"], "ID")
+		}
+		if err := requireFields(associatedType, as.foreignFields); err != nil {
+			return fmt.Errorf(errMsg+"foreign field %s, please set it explicitly", as.fieldName, err)
+		}
+		if err := requireFields(parentType, as.referFields); err != nil {
+			return fmt.Errorf(errMsg+"refer field %s, please set it explicitly", as.fieldName, err)
+		}
+		if len(as.foreignKeys) == 0 {
+			as.foreignKeys = fieldColumns(associatedType, as.foreignFields)
+		}
+	case ManyToMany:
+		if as.joinTable == nil && settings["MANY2MANY"] != "" {
+			as.joinTable = &joinTable{tableName: settings["MANY2MANY"]}
+		}
+		// the foreignKey and references of gorm many2many tag refer to the current and associated model respectively
+		if len(as.referFields) == 0 {
+			as.referFields = tagutil.SplitTagValue(settings["FOREIGNKEY"], "ID")
+		}
+		if len(as.foreignFields) == 0 {
+			as.foreignFields = tagutil.SplitTagValue(settings["REFERENCES"], "ID")
+		}
+		if err := requireFields(parentType, as.referFields); err != nil {
+			return fmt.Errorf(errMsg+"refer field %s, please set it explicitly", as.fieldName, err)
+		}
+		if err := requireFields(associatedType, as.foreignFields); err != nil {
+			return fmt.Errorf(errMsg+"foreign field %s, please set it explicitly", as.fieldName, err)
+		}
+		if len(as.referCols) == 0 {
+			as.referCols = tagutil.JoinColumns(settings["JOINFOREIGNKEY"], parentType.Name(), as.referFields)
+		}
+		if len(as.foreignKeys) == 0 {
+			as.foreignKeys = tagutil.JoinColumns(settings["JOINREFERENCES"], associatedType.Name(), as.foreignFields)
+		}
+		if as.associatedField == "" {
+			associatedField, err := findSliceField(associatedType, parentType)
+			if err != nil {
+				return fmt.Errorf(errMsg+"associated field %s, please set it explicitly", as.fieldName, err)
+			}
+			as.associatedField = associatedField
+		}
+	}
+	return nil
+}
+
+func requireFields(objType reflect.Type, fields []string) error {
+	for _, field := range fields {
+		if _, ok := objType.FieldByName(field); !ok {
+			return fmt.Errorf("%s not found in %s", field, objType.Name())
+		}
+	}
+	return nil
+}
+
+func fieldColumns(objType reflect.Type, fields []string) []string {
+	cols := make([]string, len(fields))
+	for i, field := range fields {
+		structField, _ := objType.FieldByName(field)
+		cols[i] = columnName(structField)
+	}
+	return cols
+}
+
+// columnName get the column name of field from TagProcess, db tag, gorm column tag or the snake case of field name
+func columnName(field reflect.StructField) string {
+	if options.TagProcess != nil {
+		if col := options.TagProcess(field.Tag); col != "" {
+			return col
+		}
+	}
+	if col := strings.Split(field.Tag.Get("db"), ",")[0]; col != "" && col != "-" {
+		return col
+	}
+	if col := GormTagProcess(field.Tag); col != "" {
+		return col
+	}
+	return tagutil.ToSnakeCase(field.Name)
+}
+
+// findSliceField find the only slice field of objType whose element is elemType
+func findSliceField(objType reflect.Type, elemType reflect.Type) (string, error) {
+	fields := make([]tagutil.SliceField, 0, objType.NumField())
+	for i := 0; i < objType.NumField(); i++ {
+		field := objType.Field(i)
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		fieldElem := field.Type.Elem()
+		if fieldElem.Kind() == reflect.Ptr {
+			fieldElem = fieldElem.Elem()
+		}
+		fields = append(fields, tagutil.SliceField{Name: field.Name, Elem: fieldElem.String()})
+	}
+	return tagutil.FindSliceField(fields, elemType.String())
+}
@@ -0,0 +1,110 @@
+// Package tagutil parse the gorm tags and name the tables and columns of associations, it is shared by the runtime
+// inference of gofactory and the code generator of codegen
+package tagutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseGormTag parse the value of gorm tag into upper case keys and values (e.g foreignKey:DomainID to FOREIGNKEY),
+// the key without value (e.g primaryKey) is set as its own value
+func ParseGormTag(tag string) map[string]string {
+	settings := make(map[string]string)
+	for _, item := range strings.Split(tag, ";") {
+		kv := strings.SplitN(item, ":", 2)
+		key := strings.ToUpper(strings.TrimSpace(kv[0]))
+		if key == "" {
+			continue
+		}
+		if len(kv) == 2 {
+			settings[key] = strings.TrimSpace(kv[1])
+		} else {
+			settings[key] = key
+		}
+	}
+	return settings
+}
+
+// SplitTagValue split the comma separated value of tag setting, the default value is used if value is empty
+func SplitTagValue(value string, defaultValue string) []string {
+	if value == "" {
+		return []string{defaultValue}
+	}
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+// JoinColumns get the columns of join table from the joinForeignKey or joinReferences setting, the snake case of
+// model name and field (e.g user_id) is used if the setting is empty
+func JoinColumns(joinKeys string, modelName string, fields []string) []string {
+	if joinKeys != "" {
+		keys := SplitTagValue(joinKeys, "")
+		for i := range keys {
+			keys[i] = ToSnakeCase(keys[i])
+		}
+		return keys
+	}
+	cols := make([]string, len(fields))
+	for i, field := range fields {
+		cols[i] = ToSnakeCase(modelName + field)
+	}
+	return cols
+}
+
+// ToSnakeCase convert field name to snake case column name (e.g DomainID to domain_id)
+func ToSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// Pluralize get the plural form of the snake case name (e.g category to categories)
+func Pluralize(name string) string {
+	switch {
+	case name == "":
+		return name
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// SliceField the slice field of struct, Elem is the name of its element type
+type SliceField struct {
+	Name string
+	Elem string
+}
+
+// FindSliceField find the only slice field whose element is elem, the field is ambiguous if none or more than one is
+// found
+func FindSliceField(fields []SliceField, elem string) (string, error) {
+	found := make([]string, 0, 1)
+	for _, field := range fields {
+		if field.Elem == elem {
+			found = append(found, field.Name)
+		}
+	}
+	if len(found) != 1 {
+		return "", fmt.Errorf("of type []%s is ambiguous, candidates: %v", elem, found)
+	}
+	return found[0], nil
+}
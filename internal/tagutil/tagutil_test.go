@@ -0,0 +1,61 @@
+package tagutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"ID":             "id",
+		"DomainID":       "domain_id",
+		"OrganizationID": "organization_id",
+		"HTTPServer":     "http_server",
+		"UserURL":        "user_url",
+		"CreatedAt":      "created_at",
+	}
+	for in, out := range cases {
+		assert.Equal(t, out, ToSnakeCase(in))
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	cases := map[string]string{
+		"user":     "users",
+		"category": "categories",
+		"day":      "days",
+		"box":      "boxes",
+		"branch":   "branches",
+		"":         "",
+	}
+	for in, out := range cases {
+		assert.Equal(t, out, Pluralize(in))
+	}
+}
+
+func TestParseGormTag(t *testing.T) {
+	settings := ParseGormTag("foreignKey:TenantID,OrganizationID; references:TenantID,ID;many2many:user_languages;-")
+	assert.Equal(t, "TenantID,OrganizationID", settings["FOREIGNKEY"])
+	assert.Equal(t, "TenantID,ID", settings["REFERENCES"])
+	assert.Equal(t, "user_languages", settings["MANY2MANY"])
+	assert.Equal(t, []string{"TenantID", "OrganizationID"}, SplitTagValue(settings["FOREIGNKEY"], "ID"))
+	assert.Equal(t, []string{"ID"}, SplitTagValue(settings["REFERENCES2"], "ID"))
+}
+
+func TestJoinColumns(t *testing.T) {
+	assert.Equal(t, []string{"user_id", "user_tenant_id"}, JoinColumns("", "User", []string{"ID", "TenantID"}))
+	assert.Equal(t, []string{"person_id"}, JoinColumns("PersonID", "User", []string{"ID"}))
+}
+
+func TestFindSliceField(t *testing.T) {
+	fields := []SliceField{{Name: "Users", Elem: "User"}, {Name: "Tags", Elem: "Tag"}}
+	name, err := FindSliceField(fields, "User")
+	assert.NoError(t, err)
+	assert.Equal(t, "Users", name)
+
+	_, err = FindSliceField(append(fields, SliceField{Name: "Admins", Elem: "User"}), "User")
+	assert.Error(t, err)
+	_, err = FindSliceField(fields, "Role")
+	assert.Error(t, err)
+}
@@ -1,10 +1,14 @@
 package gofactory
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"strings"
 
 	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/internal/tagutil"
 	"github.com/vx416/gogo-factory/reflectutil"
 )
 
@@ -31,7 +35,12 @@ func (setter ObjectSetter) buildFieldColumns(obj interface{}) map[string]string
 
 // SetupObject setup object with Attributers
 func (setter ObjectSetter) SetupObject(val reflect.Value, omits map[string]bool, only map[string]bool) error {
-	data := val.Interface()
+	return setter.SetupObjectAt(val, 0, omits, only)
+}
+
+// SetupObjectAt setup the object built at index (e.g the index of BuildN) with Attributers
+func (setter ObjectSetter) SetupObjectAt(val reflect.Value, index int, omits map[string]bool, only map[string]bool) error {
+	ctx := attr.NewContext(val.Interface(), index)
 	if val.Kind() != reflect.Ptr {
 		return fmt.Errorf("setup object: object should be a pointer")
 	}
@@ -45,7 +54,7 @@ func (setter ObjectSetter) SetupObject(val reflect.Value, omits map[string]bool,
 			if omits[attrItem.Name()] {
 				continue
 			}
-			err := setter.setField(data, val, attrItem)
+			err := setter.setField(ctx, val, attrItem)
 			if err != nil {
 				return err
 			}
@@ -57,7 +66,7 @@ func (setter ObjectSetter) SetupObject(val reflect.Value, omits map[string]bool,
 		if omits[attrItem.Name()] {
 			continue
 		}
-		err := setter.setField(data, val, attrItem)
+		err := setter.setField(ctx, val, attrItem)
 		if err != nil {
 			return err
 		}
@@ -65,12 +74,12 @@ func (setter ObjectSetter) SetupObject(val reflect.Value, omits map[string]bool,
 	return nil
 }
 
-func (setter ObjectSetter) setField(data interface{}, val reflect.Value, attrItem attr.Attributer) error {
+func (setter ObjectSetter) setField(ctx attr.Context, val reflect.Value, attrItem attr.Attributer) error {
 	field, fieldType, found := reflectutil.FindField(val, attrItem.Name())
 	if !found {
 		return fmt.Errorf("setup object: object field(%s) not found", attrItem.Name())
 	}
-	_, err := attr.SetField(data, field, fieldType, attrItem)
+	_, err := attr.SetFieldWithContext(ctx, field, fieldType, attrItem)
 	if err != nil {
 		return err
 	}
@@ -105,19 +114,79 @@ func getColumnValues(val reflect.Value, fieldColumn map[string]string) map[strin
 
 	columnValues := make(map[string]interface{})
 	for field, column := range fieldColumn {
-		field := val.FieldByName(field)
+		field, structField, found := reflectutil.LookupField(val, field)
+		if !found {
+			continue
+		}
 		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
 			field = field.Elem()
 		}
 		if !field.IsZero() {
-			val := field.Interface()
-			columnValues[column] = val
+			columnValues[column] = columnValue(field, jsonColumn(structField))
 		}
 	}
 
 	return columnValues
 }
 
+// nullColumns get the columns of the fields which are set to NULL by the attributes (e.g attr.Nullable), NULL is
+// inserted into the columns instead of omitting them
+func (setter ObjectSetter) nullColumns(val reflect.Value, fieldColumns map[string]string, omits map[string]bool, only map[string]bool) []string {
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	columns := make([]string, 0)
+	for _, attrItem := range setter {
+		if omits[attrItem.Name()] || (len(only) > 0 && !only[attrItem.Name()]) {
+			continue
+		}
+		column := fieldColumns[attrItem.Name()]
+		if column == "" {
+			continue
+		}
+		field, _, found := reflectutil.LookupField(val, attrItem.Name())
+		if found && reflectutil.IsNull(field) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// columnValue get the value inserted into column, the big integers are formatted (e.g NUMERIC column), and the values
+// of JSON column which don't implement driver.Valuer are encoded as JSON (e.g the nested struct of JSONB column), the
+// other values (e.g []byte) are inserted as they are
+func columnValue(field reflect.Value, isJSON bool) interface{} {
+	val := field.Interface()
+	if _, ok := reflectutil.IsValuer(field); ok {
+		return val
+	}
+	if v, ok := val.(big.Int); ok {
+		return v.String()
+	}
+	if !isJSON {
+		return val
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return val
+	}
+	return string(data)
+}
+
+// jsonColumn check the field is the JSON column tagged by gorm serializer:json, type:json or type:jsonb
+func jsonColumn(field reflect.StructField) bool {
+	settings := tagutil.ParseGormTag(field.Tag.Get("gorm"))
+	if strings.EqualFold(settings["SERIALIZER"], "json") {
+		return true
+	}
+	columnType := strings.ToLower(settings["TYPE"])
+	return columnType == "json" || columnType == "jsonb"
+}
+
 // TagGetter tag getter
 type TagGetter interface {
 	Get(tagName string) (tagString string)
@@ -127,21 +196,56 @@ type TagProcess func(tagGetter TagGetter) string
 
 func getObjectColumnNames(val reflect.Value, tagProcess TagProcess) map[string]string {
 	objType := val.Type()
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-		objType = val.Type()
+	if objType.Kind() == reflect.Ptr {
+		objType = objType.Elem()
 	}
 
 	filedColumns := make(map[string]string)
+	addStructColumns(filedColumns, objType, "", "", tagProcess)
+	return filedColumns
+}
 
-	for i := 0; i < objType.NumField(); i++ {
-		field := objType.Field(i)
-		fieldName := field.Name
+// addStructColumns add the columns of struct fields, the fields of embedded structs are flattened, the promoted fields
+// of anonymous structs are keyed by name, the fields of gorm embedded structs are keyed by path (e.g Author.Name),
+// and the columns are prefixed by gorm embeddedPrefix tag. the fields of outer struct take precedence over promoted fields
+func addStructColumns(fieldColumns map[string]string, structType reflect.Type, path string, prefix string, tagProcess TagProcess) {
+	embedded := make([]reflect.StructField, 0)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
 		colName := tagProcess(field.Tag)
-		if colName != "" {
-			filedColumns[fieldName] = colName
+		if colName == "" {
+			if _, _, ok := embeddedStruct(field); ok {
+				embedded = append(embedded, field)
+			}
+			continue
+		}
+		if _, ok := fieldColumns[path+field.Name]; !ok {
+			fieldColumns[path+field.Name] = prefix + colName
 		}
 	}
 
-	return filedColumns
+	for _, field := range embedded {
+		embeddedType, embeddedPrefix, _ := embeddedStruct(field)
+		fieldPath := path
+		if !field.Anonymous {
+			fieldPath = path + field.Name + "."
+		}
+		addStructColumns(fieldColumns, embeddedType, fieldPath, prefix+embeddedPrefix, tagProcess)
+	}
+}
+
+// embeddedStruct get the struct type of anonymous field or the field tagged by gorm embedded, and the column prefix
+func embeddedStruct(field reflect.StructField) (reflect.Type, string, bool) {
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct {
+		return nil, "", false
+	}
+	settings := tagutil.ParseGormTag(field.Tag.Get("gorm"))
+	if _, ok := settings["EMBEDDED"]; !ok && !field.Anonymous {
+		return nil, "", false
+	}
+	return fieldType, settings["EMBEDDEDPREFIX"], true
 }
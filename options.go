@@ -7,6 +7,9 @@ import (
 	"github.com/vx416/gogo-factory/dbutil"
 )
 
+// DefaultMaxDepth the default maximum depth of nested associations
+const DefaultMaxDepth int32 = 32
+
 var options = &Options{}
 
 // Options global option for factory context
@@ -15,6 +18,7 @@ type Options struct {
 	Driver     string
 	InsertFunc dbutil.InsertFunc
 	TagProcess TagProcess
+	MaxDepth   int32
 }
 
 // SetDB setup db instance
@@ -36,6 +40,20 @@ func (opt *Options) SetTagProcess(tp TagProcess) *Options {
 	return opt
 }
 
+// SetMaxDepth setup the maximum depth of nested associations, building deeper associations
+// return an error instead of recursing forever
+func (opt *Options) SetMaxDepth(depth int32) *Options {
+	opt.MaxDepth = depth
+	return opt
+}
+
+func (opt *Options) maxDepth() int32 {
+	if opt.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return opt.MaxDepth
+}
+
 // Opt get global options
 func Opt() *Options {
 	return options
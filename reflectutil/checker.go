@@ -56,3 +56,70 @@ func IsScanner(field reflect.Value) (sql.Scanner, bool) {
 	}
 	return nil, false
 }
+
+// IsNull check the field is NULL, the nil pointer and the driver.Valuer field of which value is nil (e.g invalid
+// sql.NullString) are NULL
+func IsNull(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if field.IsNil() {
+			return true
+		}
+	}
+	valuer, ok := IsValuer(field)
+	if !ok {
+		return false
+	}
+	value, err := valuer.Value()
+	return err == nil && value == nil
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// CompatibleKeyTypes check the value of key field typed a can be assigned to the key field typed b, e.g int64 and
+// sql.NullInt32 are compatible, but int64 and string are not. the other scanners and valuers (e.g uuid.UUID) are
+// assumed to be compatible since their values are converted at runtime
+func CompatibleKeyTypes(a, b reflect.Type) bool {
+	a, b = keyType(a), keyType(b)
+	switch {
+	case a == b:
+		return true
+	case isSQLType(a), isSQLType(b):
+		return true
+	case isNumeric(a.Kind()), isNumeric(b.Kind()):
+		return isNumeric(a.Kind()) && isNumeric(b.Kind())
+	case a.Kind() == reflect.String, b.Kind() == reflect.String:
+		return a.Kind() == b.Kind()
+	}
+	return a.ConvertibleTo(b)
+}
+
+// keyType get the type of value stored by the key field, the pointers are dereferenced and the value of nullable
+// wrappers (e.g sql.NullInt64, null.Int) is unwrapped
+func keyType(typ reflect.Type) reflect.Type {
+	for {
+		switch {
+		case typ.Kind() == reflect.Ptr:
+			typ = typ.Elem()
+		case typ.Kind() == reflect.Struct && typ.NumField() == 1:
+			typ = typ.Field(0).Type
+		case typ.Kind() == reflect.Struct && typ.NumField() == 2 && typ.Field(1).Name == "Valid" &&
+			typ.Field(1).Type.Kind() == reflect.Bool:
+			typ = typ.Field(0).Type
+		default:
+			return typ
+		}
+	}
+}
+
+func isSQLType(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(scannerType) || typ.Implements(valuerType) || ptr.Implements(valuerType)
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	return isInt(kind) || isUint(kind) || isFloat(kind)
+}
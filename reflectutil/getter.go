@@ -1,6 +1,9 @@
 package reflectutil
 
-import "reflect"
+import (
+	"reflect"
+	"strings"
+)
 
 func GetFieldElem(val reflect.Value, fieldName string) reflect.Value {
 	if val.Kind() == reflect.Ptr {
@@ -33,16 +36,59 @@ func GetElem(data interface{}) reflect.Value {
 	return val
 }
 
+// FindField find the field by name or the path of nested struct fields (e.g Author.Name), the promoted fields of
+// embedded structs are found by name as well, and the nil pointers to the structs on the path are allocated
 func FindField(val reflect.Value, fieldName string) (reflect.Value, reflect.StructField, bool) {
-	field := val.FieldByName(fieldName)
-	if !field.IsValid() {
-		return reflect.Value{}, reflect.StructField{}, false
+	return findField(val, fieldName, true)
+}
+
+// LookupField find the field as FindField without allocating, it returns false if a pointer on the path is nil
+func LookupField(val reflect.Value, fieldName string) (reflect.Value, reflect.StructField, bool) {
+	return findField(val, fieldName, false)
+}
+
+func findField(val reflect.Value, fieldName string, alloc bool) (reflect.Value, reflect.StructField, bool) {
+	var (
+		fieldType reflect.StructField
+		found     bool
+	)
+	for i, name := range strings.Split(fieldName, ".") {
+		if i > 0 {
+			if val, found = indirect(val, alloc); !found {
+				return reflect.Value{}, reflect.StructField{}, false
+			}
+		}
+		if val.Kind() != reflect.Struct {
+			return reflect.Value{}, reflect.StructField{}, false
+		}
+		fieldType, found = val.Type().FieldByName(name)
+		if !found {
+			return reflect.Value{}, reflect.StructField{}, false
+		}
+		for j, index := range fieldType.Index {
+			if j > 0 {
+				if val, found = indirect(val, alloc); !found {
+					return reflect.Value{}, reflect.StructField{}, false
+				}
+			}
+			val = val.Field(index)
+		}
+	}
+	return val, fieldType, true
+}
+
+// indirect get the element of pointer, the nil pointer is allocated if alloc is true and it can be set
+func indirect(val reflect.Value, alloc bool) (reflect.Value, bool) {
+	if val.Kind() != reflect.Ptr {
+		return val, true
 	}
-	fieldType, found := val.Type().FieldByName(fieldName)
-	if !found {
-		return reflect.Value{}, reflect.StructField{}, false
+	if val.IsNil() {
+		if !alloc || !val.CanSet() {
+			return reflect.Value{}, false
+		}
+		val.Set(reflect.New(val.Type().Elem()))
 	}
-	return field, fieldType, true
+	return val.Elem(), true
 }
 
 func MakeSlice(data interface{}, cap int) reflect.Value {
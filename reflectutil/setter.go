@@ -0,0 +1,138 @@
+package reflectutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// SetValue set the value to the field, the value is assigned directly if it is assignable, otherwise it is converted
+// to the field type (e.g int to int32 or named type Gender), the nil pointer field is allocated if the value is not a
+// pointer, and the error is returned if the value cannot be converted or overflows the field type
+func SetValue(field reflect.Value, data interface{}) error {
+	if !field.CanSet() {
+		return fmt.Errorf("set value: field of %s cannot be set", field.Type())
+	}
+	if data == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	val := reflect.ValueOf(data)
+	if val.Type().AssignableTo(field.Type()) {
+		field.Set(val)
+		return nil
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		if field.Kind() != reflect.Ptr {
+			return SetValue(field, val.Elem().Interface())
+		}
+	}
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := SetValue(elem.Elem(), data); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	if val.Kind() == reflect.Slice && field.Kind() == reflect.Slice && !val.Type().ConvertibleTo(field.Type()) {
+		slice := reflect.MakeSlice(field.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			if err := SetValue(slice.Index(i), val.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	converted, err := convertValue(val, field.Type())
+	if err != nil {
+		return err
+	}
+	field.Set(converted)
+	return nil
+}
+
+// convertValue convert the value to the type, the numeric value is checked for overflow and the float value should be
+// integral if it is converted to integer
+func convertValue(val reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	errConvert := fmt.Errorf("set value: %v (%s) cannot be converted to %s", val.Interface(), val.Type(), typ)
+	if !val.Type().ConvertibleTo(typ) {
+		return reflect.Value{}, errConvert
+	}
+
+	switch {
+	case isInt(val.Kind()):
+		n := val.Int()
+		switch {
+		case isInt(typ.Kind()):
+			if reflect.Zero(typ).OverflowInt(n) {
+				return reflect.Value{}, fmt.Errorf("set value: %d overflows %s", n, typ)
+			}
+		case isUint(typ.Kind()):
+			if n < 0 || reflect.Zero(typ).OverflowUint(uint64(n)) {
+				return reflect.Value{}, fmt.Errorf("set value: %d overflows %s", n, typ)
+			}
+		case isFloat(typ.Kind()):
+		default:
+			return reflect.Value{}, errConvert
+		}
+	case isUint(val.Kind()):
+		n := val.Uint()
+		switch {
+		case isInt(typ.Kind()):
+			if n > math.MaxInt64 || reflect.Zero(typ).OverflowInt(int64(n)) {
+				return reflect.Value{}, fmt.Errorf("set value: %d overflows %s", n, typ)
+			}
+		case isUint(typ.Kind()):
+			if reflect.Zero(typ).OverflowUint(n) {
+				return reflect.Value{}, fmt.Errorf("set value: %d overflows %s", n, typ)
+			}
+		case isFloat(typ.Kind()):
+		default:
+			return reflect.Value{}, errConvert
+		}
+	case isFloat(val.Kind()):
+		f := val.Float()
+		switch {
+		case isFloat(typ.Kind()):
+			if reflect.Zero(typ).OverflowFloat(f) {
+				return reflect.Value{}, fmt.Errorf("set value: %v overflows %s", f, typ)
+			}
+		case isInt(typ.Kind()), isUint(typ.Kind()):
+			if f != math.Trunc(f) {
+				return reflect.Value{}, fmt.Errorf("set value: %v is not integral for %s", f, typ)
+			}
+			if isInt(typ.Kind()) && (f < math.MinInt64 || f >= math.MaxInt64 || reflect.Zero(typ).OverflowInt(int64(f))) ||
+				isUint(typ.Kind()) && (f < 0 || f >= math.MaxUint64 || reflect.Zero(typ).OverflowUint(uint64(f))) {
+				return reflect.Value{}, fmt.Errorf("set value: %v overflows %s", f, typ)
+			}
+		default:
+			return reflect.Value{}, errConvert
+		}
+	case val.Kind() == reflect.String:
+		if typ.Kind() != reflect.String && typ.Kind() != reflect.Slice {
+			return reflect.Value{}, errConvert
+		}
+	}
+	return val.Convert(typ), nil
+}
+
+func isInt(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUint(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
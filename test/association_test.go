@@ -1,9 +1,11 @@
 package test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	factory "github.com/vx416/gogo-factory"
 	"github.com/vx416/gogo-factory/attr"
@@ -193,3 +195,172 @@ func TestAllAssociations(t *testing.T) {
 	home := homeFactory.MustBuild().(*Home)
 	assert.Nil(t, home.Location)
 }
+
+func TestPolymorphic(t *testing.T) {
+	post := PostFactory.HasManyComments(CommentFactory, 3).MustBuild().(*Post)
+	assert.Len(t, post.Comments, 3)
+	for _, comment := range post.Comments {
+		assert.Equal(t, "posts", comment.CommentableType)
+		assert.Equal(t, post.ID, comment.CommentableID)
+	}
+
+	photo := PhotoFactory.HasOneComment(CommentFactory).MustBuild().(*Photo)
+	assert.NotNil(t, photo.Comment)
+	assert.Equal(t, "photos", photo.Comment.CommentableType)
+	assert.Equal(t, photo.ID, photo.Comment.CommentableID)
+
+	postAss := PostFactory.ToAssociation().ReferField("ID").ForeignField("CommentableID").ForeignKey("commentable_id").
+		Polymorphic("CommentableType", "commentable_type", "posts")
+	comment := CommentFactory.BelongsTo("Post", postAss).MustBuild().(*Comment)
+	assert.NotNil(t, comment.Post)
+	assert.Equal(t, "posts", comment.CommentableType)
+	assert.Equal(t, comment.Post.ID, comment.CommentableID)
+}
+
+func countCategories(category *Category) int {
+	count := 1
+	for _, child := range category.Children {
+		count += countCategories(child)
+	}
+	return count
+}
+
+func TestSelfRef(t *testing.T) {
+	category := CategoryFactory.WithParents(3).MustBuild().(*Category)
+	depth := 0
+	for curr := category; curr.Parent != nil; curr = curr.Parent {
+		assert.True(t, curr.ParentID.Valid)
+		assert.Equal(t, curr.Parent.ID, curr.ParentID.Int64)
+		assert.Nil(t, curr.Children)
+		depth++
+	}
+	assert.Equal(t, 3, depth)
+
+	root := CategoryFactory.WithChildren(2, 3).MustBuild().(*Category)
+	assert.Equal(t, 1+2+4+8, countCategories(root))
+	assert.Nil(t, root.Parent)
+	for _, child := range root.Children {
+		assert.Equal(t, root.ID, child.ParentID.Int64)
+		assert.Len(t, child.Children, 2)
+	}
+
+	_, err := CategoryFactory.WithParents(0).Build()
+	assert.Error(t, err)
+
+	factory.Opt().SetMaxDepth(2)
+	_, err = CategoryFactory.WithParents(3).Build()
+	assert.Error(t, err)
+	factory.Opt().SetMaxDepth(0)
+}
+
+func TestCompositeForeignKeys(t *testing.T) {
+	org := OrganizationFactory.HasManyMembers(MemberFactory, 3).MustBuild().(*Organization)
+	assert.Len(t, org.Members, 3)
+	for _, member := range org.Members {
+		assert.Equal(t, org.TenantID, member.TenantID)
+		assert.Equal(t, org.ID, member.OrganizationID.Int64)
+	}
+
+	member := MemberFactory.BelongsToOrganization(OrganizationFactory).MustBuild().(*Member)
+	assert.NotNil(t, member.Organization)
+	assert.Equal(t, member.Organization.TenantID, member.TenantID)
+	assert.Equal(t, member.Organization.ID, member.OrganizationID.Int64)
+
+	mismatchAss := OrganizationFactory.ToAssociation().ReferFields("TenantID", "ID").ForeignFields("TenantID")
+	_, err := MemberFactory.BelongsTo("Organization", mismatchAss).Build()
+	assert.Error(t, err)
+
+	typeMismatchAss := OrganizationFactory.ToAssociation().ReferFields("TenantID", "Name").ForeignFields("TenantID", "OrganizationID")
+	_, err = MemberFactory.BelongsTo("Organization", typeMismatchAss).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refer field(Name) of type string and foreign field(OrganizationID) of type sql.NullInt64 are incompatible")
+
+	typeMismatchAss = MemberFactory.ToAssociation().ReferFields("TenantID", "Name").ForeignFields("TenantID", "OrganizationID")
+	_, err = OrganizationFactory.HasMany("Members", typeMismatchAss, 2).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "are incompatible")
+}
+
+func TestInferKeys(t *testing.T) {
+	spec := SpecialtyFactory.BelongsTo("Domain", DomainFactory.ToAssociation().Infer()).MustBuild().(*Specialty)
+	assert.NotNil(t, spec.Domain)
+	assert.Equal(t, spec.Domain.ID, spec.DomainID.Int64)
+
+	project := ProjectFactory.HasMany("Tasks", TaskFactory.ToAssociation().Infer(), 3).MustBuild().(*Project)
+	assert.Len(t, project.Tasks, 3)
+	for _, task := range project.Tasks {
+		assert.Equal(t, project.ID, task.ProjectID)
+	}
+
+	post := PostFactory.HasMany("Comments", CommentFactory.ToAssociation().Infer(), 2).MustBuild().(*Post)
+	for _, comment := range post.Comments {
+		assert.Equal(t, "posts", comment.CommentableType)
+		assert.Equal(t, post.ID, comment.CommentableID)
+	}
+
+	// the keys are inferred for each build, so the factories sharing the association infer their own type value
+	commentFactory := factory.New(&Comment{}, attr.Int("ID", genutil.FixInt(1), "id"))
+	videoComments := factory.New(&Video{}, attr.Int("ID", genutil.FixInt(1), "id")).Table("videos").
+		HasMany("Comments", commentFactory.ToAssociation().Infer(), 1)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			video := videoComments.MustBuild().(*Video)
+			assert.Equal(t, "videos", video.Comments[0].CommentableType)
+		}()
+	}
+	wg.Wait()
+	archived := videoComments.Table("archived_videos").MustBuild().(*Video)
+	assert.Equal(t, "archived_videos", archived.Comments[0].CommentableType)
+
+	// the type value is the conventional table name if polymorphicValue and the table of parent are not set
+	videoFactory := factory.New(&Video{}, idAttr())
+	video := videoFactory.HasMany("Comments", CommentFactory.ToAssociation().Infer(), 2).MustBuild().(*Video)
+	for _, comment := range video.Comments {
+		assert.Equal(t, "videos", comment.CommentableType)
+		assert.Equal(t, video.ID, comment.CommentableID)
+	}
+
+	org := OrganizationFactory.HasMany("Members", MemberFactory.ToAssociation().Infer(), 2).MustBuild().(*Organization)
+	for _, member := range org.Members {
+		assert.Equal(t, org.TenantID, member.TenantID)
+		assert.Equal(t, org.ID, member.OrganizationID.Int64)
+	}
+
+	employee := EmployeeFactory.ManyToMany("Projects", ProjectFactory.ToAssociation().Infer(), 2).MustBuild().(*Employee)
+	assert.Len(t, employee.Projects, 2)
+	for _, project := range employee.Projects {
+		assert.Len(t, project.Employees, 1)
+	}
+
+	_, err := EmployeeFactory.HasOne("Specialty", SpecialtyFactory.ToAssociation().Infer()).Build()
+	assert.Error(t, err, "Specialty has no EmployeeID field")
+	employee = EmployeeFactory.HasOne("Specialty", SpecialtyFactory.ToAssociation().ForeignField("OwnerID").Infer()).MustBuild().(*Employee)
+	assert.Equal(t, employee.ID, employee.Specialty.OwnerID.Int64)
+}
+
+func TestInverse(t *testing.T) {
+	project := ProjectFactory.HasMany("Tasks", TaskFactory.ToAssociation().Infer().Inverse("Project"), 3).MustBuild().(*Project)
+	assert.Len(t, project.Tasks, 3)
+	for _, task := range project.Tasks {
+		assert.Same(t, project, task.Project)
+	}
+
+	spec := SpecialtyFactory.BelongsTo("Domain", DomainFactory.ToAssociation().Infer().Inverse("Specialties")).MustBuild().(*Specialty)
+	assert.Len(t, spec.Domain.Specialties, 1)
+	assert.Same(t, spec, spec.Domain.Specialties[0])
+
+	prjAss := ProjectFactory.ToAssociation().ReferField("ID").ReferColumn("employee_id").
+		ForeignField("ID").ForeignKey("project_id").Inverse("Employees")
+	employee := EmployeeFactory.ManyToMany("Projects", prjAss, 2).MustBuild().(*Employee)
+	assert.Len(t, employee.Projects, 2)
+	for _, project := range employee.Projects {
+		assert.Len(t, project.Employees, 1)
+		assert.Same(t, employee, project.Employees[0])
+	}
+
+	_, err := ProjectFactory.HasMany("Tasks", TaskFactory.ToAssociation().Infer().Inverse("Name"), 1).Build()
+	assert.Error(t, err)
+}
@@ -1,16 +1,22 @@
 package test
 
 import (
+	"database/sql"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/vx416/gogo-factory/genutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Pallinder/go-randomdata"
 	factory "github.com/vx416/gogo-factory"
 	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/dbutil"
+	"gopkg.in/guregu/null.v4"
 )
 
 func between(t *testing.T, target, max, min int) {
@@ -149,3 +155,257 @@ func TestOverWrite(t *testing.T) {
 	assert.NotNil(t, e2.Age)
 	assert.Equal(t, e2.Gender, Gender(3))
 }
+
+func TestFnAttributes(t *testing.T) {
+	employeeFactory := factory.New(
+		&Employee{},
+		attr.Int("ID", genutil.SeqInt(1, 1)),
+		attr.StrFn("Name", func(obj interface{}) string {
+			return fmt.Sprintf("employee-%d", obj.(*Employee).ID)
+		}),
+		attr.FnOf("Phone", func(e *Employee, index int) string {
+			return fmt.Sprintf("%s/%d", e.Name, index)
+		}),
+		attr.Fn("Salary", func(ctx attr.Context) float64 {
+			return float64(ctx.Index) * 100
+		}),
+	)
+
+	employees := employeeFactory.MustBuildN(3).([]*Employee)
+	for i, e := range employees {
+		assert.Equal(t, fmt.Sprintf("employee-%d", e.ID), e.Name)
+		assert.Equal(t, fmt.Sprintf("%s/%d", e.Name, i), e.Phone)
+		assert.Equal(t, float64(i)*100, e.Salary)
+	}
+
+	e := employeeFactory.MustBuild().(*Employee)
+	assert.Equal(t, e.Name+"/0", e.Phone)
+
+	_, err := factory.New(&User{}, attr.FnOf("Username", func(e *Employee, index int) string {
+		return e.Name
+	})).Build()
+	assert.Error(t, err)
+}
+
+func TestConcurrentProcessAttributes(t *testing.T) {
+	nameAttr := attr.Str("Name", genutil.FixStr("employee")).Process(func(a attr.Attributer) error {
+		e := a.GetObject().(*Employee)
+		return a.SetVal(fmt.Sprintf("%s-%d", a.GetVal(), e.ID))
+	})
+	phoneAttr := attr.FnOf("Phone", func(e *Employee, index int) string {
+		return e.Name + "/phone"
+	})
+
+	var wg sync.WaitGroup
+	employees := make([]*Employee, 20)
+	for i := range employees {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			idAttr := attr.Of("ID", func() int64 { return int64(i) })
+			employees[i] = factory.New(&Employee{}, idAttr, nameAttr, phoneAttr).MustBuild().(*Employee)
+		}(i)
+	}
+	wg.Wait()
+	for i, e := range employees {
+		assert.Equal(t, fmt.Sprintf("employee-%d", i), e.Name)
+		assert.Equal(t, e.Name+"/phone", e.Phone)
+	}
+}
+
+func TestGenericAttributes(t *testing.T) {
+	ptrString := "ptr"
+	userFactory := factory.New(
+		&User{},
+		attr.Of("ID", genutil.FixInt(7)),
+		attr.Of("Gender", func() Gender { return 2 }),
+		attr.Of("Age", func() int64 { return 30 }),
+		attr.Of("Height", func() float64 { return 170.5 }),
+		attr.Of("PtrString", func() *string { return &ptrString }),
+		attr.Of("Home", func() Home { return Home{ID: 3} }),
+		attr.Of("Rented", func() []*Home { return []*Home{{ID: 4}, {ID: 5}} }),
+		attr.Attr("Weight", func() interface{} { return uint8(60) }),
+	)
+
+	user, err := userFactory.Build()
+	require.NoError(t, err)
+	u := user.(*User)
+	assert.Equal(t, int64(7), u.ID)
+	assert.Equal(t, Gender(2), u.Gender)
+	assert.Equal(t, int32(30), *u.Age)
+	assert.Equal(t, float32(170.5), u.Height)
+	assert.Equal(t, &ptrString, u.PtrString)
+	assert.Equal(t, int64(3), u.Home.ID)
+	assert.Len(t, u.Rented, 2)
+	assert.Equal(t, float32(60), u.Weight)
+
+	_, err = userFactory.Attrs(attr.Int("Gender", genutil.FixInt(1000))).Build()
+	assert.Error(t, err)
+	_, err = userFactory.Attrs(attr.Float("ID", genutil.FixFloat(1.5))).Build()
+	assert.Error(t, err)
+	_, err = userFactory.Attrs(attr.Str("Age", genutil.FixStr("30"))).Build()
+	assert.Error(t, err)
+}
+
+type NullableProfile struct {
+	ID       int64
+	Age      *int32
+	Nickname sql.NullString
+	Bio      null.String
+	SeenAt   sql.NullTime
+}
+
+func TestNullableAttributes(t *testing.T) {
+	profileFactory := factory.New(
+		&NullableProfile{},
+		attr.Int("ID", genutil.SeqInt(1, 1), "id"),
+		attr.Nullable(attr.Int("Age", genutil.FixInt(30), "age"), 1),
+		attr.Nullable(attr.Str("Nickname", genutil.FixStr("bob"), "nickname"), 1),
+		attr.Nullable(attr.Str("Bio", genutil.FixStr("hi"), "bio"), 1),
+		attr.Nullable(attr.Time("SeenAt", genutil.Now(time.UTC), "seen_at"), 1),
+	).Table("profiles")
+
+	var columnValues map[string]interface{}
+	factory.Opt().SetInsertFunc(func(job *dbutil.InsertJob) error {
+		columnValues = job.GetColumnValues()
+		return nil
+	})
+	defer factory.Opt().SetInsertFunc(nil)
+
+	p := profileFactory.MustInsert().(*NullableProfile)
+	assert.Nil(t, p.Age)
+	assert.False(t, p.Nickname.Valid)
+	assert.False(t, p.Bio.Valid)
+	assert.False(t, p.SeenAt.Valid)
+	for _, col := range []string{"age", "nickname", "bio", "seen_at"} {
+		assert.Contains(t, columnValues, col)
+		assert.Nil(t, columnValues[col], col)
+	}
+	assert.Equal(t, p.ID, columnValues["id"])
+
+	profileFactory = profileFactory.Attrs(
+		attr.Nullable(attr.Int("Age", genutil.FixInt(30), "age"), 0),
+		attr.Nullable(attr.Str("Nickname", genutil.FixStr("bob"), "nickname"), 0),
+		attr.Nullable(attr.Str("Bio", genutil.FixStr("hi"), "bio"), 0),
+	)
+	profiles, err := profileFactory.InsertN(10)
+	require.NoError(t, err)
+	for _, p := range profiles.([]*NullableProfile) {
+		require.NotNil(t, p.Age)
+		assert.Equal(t, int32(30), *p.Age)
+		assert.Equal(t, sql.NullString{String: "bob", Valid: true}, p.Nickname)
+		assert.Equal(t, null.StringFrom("hi"), p.Bio)
+	}
+	assert.Equal(t, sql.NullString{String: "bob", Valid: true}, columnValues["nickname"])
+
+	nulls := 0
+	profiles, err = profileFactory.Attrs(attr.Unique(attr.Nullable(attr.Str("Nickname", genutil.RandAlph(5), "nickname"), 0.5))).BuildN(200)
+	require.NoError(t, err)
+	for _, p := range profiles.([]*NullableProfile) {
+		if !p.Nickname.Valid {
+			nulls++
+		}
+	}
+	between(t, nulls, 150, 50)
+}
+
+var CommentFactory = factory.New(
+	&Comment{},
+	idAttr(),
+	attr.Str("Body", genutil.RandAlph(20), "body"),
+).Table("comments")
+
+type PostExt struct {
+	*factory.Factory
+}
+
+func (f *PostExt) HasManyComments(commentFactory *factory.Factory, num int32) *PostExt {
+	commentAss := commentFactory.ToAssociation().ReferField("ID").ForeignField("CommentableID").ForeignKey("commentable_id").
+		Polymorphic("CommentableType", "commentable_type", "posts")
+	return &PostExt{f.HasMany("Comments", commentAss, num)}
+}
+
+var PostFactory = &PostExt{
+	factory.New(
+		&Post{},
+		idAttr(),
+		attr.Str("Title", genutil.RandAlph(10), "title"),
+	).Table("posts"),
+}
+
+type PhotoExt struct {
+	*factory.Factory
+}
+
+func (f *PhotoExt) HasOneComment(commentFactory *factory.Factory) *PhotoExt {
+	commentAss := commentFactory.ToAssociation().ReferField("ID").ForeignField("CommentableID").ForeignKey("commentable_id").
+		Polymorphic("CommentableType", "commentable_type", "photos")
+	return &PhotoExt{f.HasOne("Comment", commentAss)}
+}
+
+var PhotoFactory = &PhotoExt{
+	factory.New(
+		&Photo{},
+		idAttr(),
+		attr.Str("URL", genutil.RandAlph(10), "url"),
+	).Table("photos"),
+}
+
+type CategoryExt struct {
+	*factory.Factory
+}
+
+func (f *CategoryExt) WithParents(depth int32) *CategoryExt {
+	parentAss := factory.SelfAssociation().ReferField("ID").ForeignField("ParentID").ForeignKey("parent_id")
+	return &CategoryExt{f.SelfRef("Parent", parentAss, depth)}
+}
+
+func (f *CategoryExt) WithChildren(num, depth int32) *CategoryExt {
+	childAss := factory.SelfAssociation().ReferField("ID").ForeignField("ParentID").ForeignKey("parent_id")
+	return &CategoryExt{f.Children("Children", childAss, num, depth)}
+}
+
+var CategoryFactory = &CategoryExt{
+	factory.New(
+		&Category{},
+		idAttr(),
+		attr.Str("Name", genutil.RandAlph(10), "name"),
+	).Table("categories"),
+}
+
+type OrganizationExt struct {
+	*factory.Factory
+}
+
+func (f *OrganizationExt) HasManyMembers(memberFactory *MemberExt, num int32) *OrganizationExt {
+	memberAss := memberFactory.ToAssociation().ReferFields("TenantID", "ID").
+		ForeignFields("TenantID", "OrganizationID").ForeignKeys("tenant_id", "organization_id")
+	return &OrganizationExt{f.HasMany("Members", memberAss, num)}
+}
+
+var OrganizationFactory = &OrganizationExt{
+	factory.New(
+		&Organization{},
+		idAttr(),
+		attr.Int("TenantID", genutil.RandInt(1, 3), "tenant_id"),
+		attr.Str("Name", genutil.RandAlph(10), "name"),
+	).Table("organizations"),
+}
+
+type MemberExt struct {
+	*factory.Factory
+}
+
+func (f *MemberExt) BelongsToOrganization(orgFactory *OrganizationExt) *MemberExt {
+	orgAss := orgFactory.ToAssociation().ReferFields("TenantID", "ID").
+		ForeignFields("TenantID", "OrganizationID").ForeignKeys("tenant_id", "organization_id")
+	return &MemberExt{f.BelongsTo("Organization", orgAss)}
+}
+
+var MemberFactory = &MemberExt{
+	factory.New(
+		&Member{},
+		idAttr(),
+		attr.Str("Name", genutil.RandName(3), "name"),
+	).Table("members"),
+}
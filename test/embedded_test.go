@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gofactory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/dbutil"
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+type BaseModel struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type Contact struct {
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+type Blog struct {
+	*BaseModel
+	Title  string  `db:"title"`
+	Author Contact `gorm:"embedded;embeddedPrefix:author_"`
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	blogFactory := gofactory.New(
+		&Blog{},
+		attr.Int("ID", genutil.SeqInt(1, 1), "id"),
+		attr.Time("CreatedAt", genutil.Now(time.UTC), "created_at"),
+		attr.Str("Title", genutil.RandAlph(10), "title"),
+		attr.Str("Author.Name", genutil.FixStr("bob"), "author_name"),
+		attr.Str("Author.Email", genutil.RandEmail(), "author_email"),
+	).Table("blogs")
+
+	blog := blogFactory.MustBuild().(*Blog)
+	require.NotNil(t, blog.BaseModel)
+	assert.NotZero(t, blog.ID)
+	assert.False(t, blog.CreatedAt.IsZero())
+	assert.Equal(t, "bob", blog.Author.Name)
+	assert.NotEmpty(t, blog.Author.Email)
+
+	columnValues := make([]map[string]interface{}, 0, 2)
+	gofactory.Opt().SetInsertFunc(func(job *dbutil.InsertJob) error {
+		columnValues = append(columnValues, job.GetColumnValues())
+		return nil
+	})
+	defer gofactory.Opt().SetInsertFunc(nil)
+
+	blog = blogFactory.MustInsert().(*Blog)
+	gofactory.Opt().SetTagProcess(gofactory.DBTagProcess)
+	defer gofactory.Opt().SetTagProcess(nil)
+	blogFactory.MustInsert()
+
+	require.Len(t, columnValues, 2)
+	for _, values := range columnValues {
+		assert.Contains(t, values, "id")
+		assert.Contains(t, values, "created_at")
+		assert.Contains(t, values, "title")
+		assert.Equal(t, "bob", values["author_name"])
+		assert.Contains(t, values, "author_email")
+	}
+	assert.Equal(t, blog.ID, columnValues[0]["id"])
+}
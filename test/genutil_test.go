@@ -0,0 +1,459 @@
+package test
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gofactory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/dbutil"
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+type Customer struct {
+	Name     string
+	Phone    string
+	Address  string
+	Company  string
+	Card     string
+	IBAN     string
+	IP       string
+	Bio      string
+	Username string
+	Password string
+}
+
+func TestFakeDataGenerators(t *testing.T) {
+	customerFactory := gofactory.New(
+		&Customer{},
+		attr.Str("Name", genutil.RandName(3)),
+		attr.Str("Phone", genutil.RandPhoneE164("DE")),
+		attr.Str("Address", genutil.RandStreetAddress("DE")),
+		attr.Str("Company", genutil.RandCompany("DE")),
+		attr.Str("Card", genutil.RandCardNumber("4", 16)),
+		attr.Str("IBAN", genutil.RandIBAN("GB")),
+		attr.Str("IP", genutil.RandIPv4()),
+		attr.Str("Bio", genutil.RandParagraph(1, 3)),
+		attr.Str("Username", genutil.RandUsername()),
+		attr.Str("Password", genutil.RandPassword(genutil.PasswordPolicy{Length: 16, MinUpper: 2, MinDigits: 2, MinSymbols: 2, Symbols: "!?"})),
+	)
+
+	genutil.Seed(42)
+	customers := customerFactory.MustBuildN(20).([]*Customer)
+	genutil.Seed(42)
+	reproduced := customerFactory.MustBuildN(20).([]*Customer)
+	assert.Equal(t, customers, reproduced)
+
+	for _, c := range customers {
+		assert.Regexp(t, regexp.MustCompile(`^\+49[1-9]\d{9}$`), c.Phone)
+		assert.Regexp(t, regexp.MustCompile(`^\S+ \d{1,3}$`), c.Address)
+		assert.True(t, strings.HasSuffix(c.Company, "GmbH") || strings.HasSuffix(c.Company, "AG") || strings.HasSuffix(c.Company, "KG"), c.Company)
+		assert.Len(t, c.Card, 16)
+		assert.True(t, genutil.ValidLuhn(c.Card), c.Card)
+		assert.Regexp(t, regexp.MustCompile(`^GB\d{2}[A-Z]{4}\d{14}$`), c.IBAN)
+		assert.True(t, genutil.ValidIBAN(c.IBAN), c.IBAN)
+		assert.Len(t, strings.Split(c.IP, "."), 4)
+		assert.True(t, strings.HasSuffix(c.Bio, "."))
+		assert.Regexp(t, regexp.MustCompile(`^[a-z]+[_.]?\d{1,3}$`), c.Username)
+
+		require.Len(t, c.Password, 16)
+		var upper, digits, symbols int
+		for _, r := range c.Password {
+			switch {
+			case unicode.IsUpper(r):
+				upper++
+			case unicode.IsDigit(r):
+				digits++
+			case strings.ContainsRune("!?", r):
+				symbols++
+			}
+		}
+		assert.GreaterOrEqual(t, upper, 2)
+		assert.GreaterOrEqual(t, digits, 2)
+		assert.GreaterOrEqual(t, symbols, 2)
+	}
+
+	assert.True(t, genutil.ValidIBAN("DE89 3704 0044 0532 0130 00"))
+	assert.False(t, genutil.ValidIBAN("DE88370400440532013000"))
+	assert.True(t, genutil.ValidLuhn("4111111111111111"))
+	assert.False(t, genutil.ValidLuhn("4111111111111112"))
+	assert.Regexp(t, regexp.MustCompile(`^\d{3}-\d{4}$`), genutil.RandPostalCode("JP")())
+
+	// the length shorter than the prefix and the check digit is clamped
+	for _, length := range []int{-1, 0, 1, 4, 5} {
+		card := genutil.RandCardNumber("4111", length)()
+		assert.True(t, strings.HasPrefix(card, "4111"), card)
+		assert.Len(t, card, 5)
+		assert.True(t, genutil.ValidLuhn(card), card)
+	}
+}
+
+type Coupon struct {
+	Code  string
+	Level int
+}
+
+func TestUniqueGenerators(t *testing.T) {
+	couponFactory := gofactory.New(
+		&Coupon{},
+		attr.Unique(attr.Str("Code", genutil.RandStrSet("a", "b", "c", "d", "e"))),
+		attr.OfErr("Level", genutil.Unique(genutil.RandInt(1, 5), 1000)),
+	)
+
+	coupons, err := couponFactory.BuildN(5)
+	require.NoError(t, err)
+	codes, levels := make(map[string]bool), make(map[int]bool)
+	for _, coupon := range coupons.([]*Coupon) {
+		codes[coupon.Code] = true
+		levels[coupon.Level] = true
+	}
+	assert.Len(t, codes, 5)
+	assert.Len(t, levels, 5)
+
+	_, err = couponFactory.Omit("Level").Build()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+	_, err = couponFactory.Omit("Code").Build()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+
+	coupon, err := couponFactory.ResetUnique().Omit("Level").Build()
+	require.NoError(t, err)
+	assert.Contains(t, "abcde", coupon.(*Coupon).Code)
+
+	// the unique attributes wrapped by Nullable and the unique groups are reset too
+	levelRecord := func() genutil.Record { return genutil.Record{"Level": genutil.RandInt(1, 1)()} }
+	wrappedFactory := gofactory.New(
+		&Coupon{},
+		attr.Nullable(attr.Unique(attr.Str("Code", genutil.RandStrSet("a")), 3), 0),
+		attr.Unique(attr.Group(levelRecord, "Level"), 3),
+	)
+	_, err = wrappedFactory.Build()
+	require.NoError(t, err)
+	_, err = wrappedFactory.Omit("Level").Build()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+	_, err = wrappedFactory.Omit("Code").Build()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+	coupon, err = wrappedFactory.ResetUnique().Build()
+	require.NoError(t, err)
+	assert.Equal(t, "a", coupon.(*Coupon).Code)
+	assert.Equal(t, 1, coupon.(*Coupon).Level)
+
+	level := genutil.Unique(genutil.RandInt(1, 1), 10)
+	val, err := level()
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	_, err = level()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+
+	set := genutil.NewUniqueSet(3)
+	for _, b := range []string{"x", "y"} {
+		val, err := set.Next(func() (interface{}, error) { return []byte(b), nil })
+		require.NoError(t, err)
+		assert.Equal(t, []byte(b), val)
+	}
+	_, err = set.Next(func() (interface{}, error) { return []byte("x"), nil })
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+	assert.Equal(t, 2, set.Len())
+}
+
+func TestDistributionGenerators(t *testing.T) {
+	genutil.Seed(7)
+	normal := genutil.RandNormal(100, 10)
+	sum := 0.0
+	for i := 0; i < 2000; i++ {
+		sum += normal()
+	}
+	assert.InDelta(t, 100, sum/2000, 2)
+
+	exp := genutil.RandExp(0.5)
+	sum = 0
+	for i := 0; i < 2000; i++ {
+		v := exp()
+		assert.GreaterOrEqual(t, v, 0.0)
+		sum += v
+	}
+	assert.InDelta(t, 2, sum/2000, 0.3)
+
+	assert.Greater(t, genutil.RandLogNormal(0, 1)(), 0.0)
+
+	zipf := genutil.RandZipf(1.2, 10)
+	counts := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		k := zipf()
+		assert.True(t, k >= 1 && k <= 10)
+		counts[k]++
+	}
+	assert.Greater(t, counts[1], counts[2])
+	assert.Greater(t, counts[2], counts[10])
+	assert.Equal(t, 1, genutil.RandZipf(1.2, 0)())
+	assert.False(t, math.IsInf(genutil.RandExp(0)(), 0))
+	assert.False(t, math.IsInf(genutil.RandExp(-1)(), 0))
+
+	weighted := genutil.Weighted(map[string]int{"active": 9, "banned": 1, "deleted": 0})
+	statuses := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		statuses[weighted()]++
+	}
+	assert.Zero(t, statuses["deleted"])
+	assert.Greater(t, statuses["active"], statuses["banned"]*4)
+
+	age := genutil.RoundInt(genutil.RandNormal(30, 20), 18, 65)
+	for i := 0; i < 100; i++ {
+		a := age()
+		assert.True(t, a >= 18 && a <= 65)
+	}
+}
+
+func TestTimeGenerators(t *testing.T) {
+	min := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	max := min.Add(60 * 24 * time.Hour)
+
+	randTime := genutil.RandTime(min, max)
+	spread := make(map[bool]bool)
+	for i := 0; i < 50; i++ {
+		tm := randTime()
+		assert.False(t, tm.Before(min) || tm.After(max))
+		spread[tm.After(min.Add(time.Hour))] = true
+	}
+	assert.True(t, spread[true], "RandTime should not always generate min")
+
+	past := genutil.PastDays(30)()
+	assert.True(t, past.After(time.Now().Add(-31*24*time.Hour)) && !past.After(time.Now()))
+	assert.True(t, genutil.FutureDays(1)().After(time.Now().Add(-time.Second)))
+
+	business := genutil.RandBusinessHours(min, max, 9, 18)
+	for i := 0; i < 100; i++ {
+		tm := business()
+		assert.NotEqual(t, time.Saturday, tm.Weekday())
+		assert.NotEqual(t, time.Sunday, tm.Weekday())
+		assert.True(t, tm.Hour() >= 9 && tm.Hour() < 18, tm)
+	}
+
+	monotonic := genutil.MonotonicTime(min, time.Minute, 30*time.Second)
+	prev := monotonic()
+	assert.Equal(t, min, prev)
+	for i := 0; i < 100; i++ {
+		next := monotonic()
+		assert.True(t, next.Sub(prev) >= 30*time.Second && next.Sub(prev) <= 90*time.Second)
+		prev = next
+	}
+}
+
+type Shipping struct {
+	ID          int64  `db:"id"`
+	City        string `db:"city"`
+	Zip         string `db:"zip"`
+	Country     string `db:"country"`
+	PostCode    string `db:"post_code"`
+	ContactName string `db:"contact_name"`
+	Email       string `db:"email"`
+}
+
+func TestGroupAttributes(t *testing.T) {
+	cities := make(map[string]string)
+	for code, locale := range genutil.Locales {
+		for _, city := range locale.Cities {
+			cities[city] = code
+		}
+	}
+
+	shippingFactory := gofactory.New(
+		&Shipping{},
+		attr.Int("ID", genutil.SeqInt(1, 1), "id"),
+		attr.Group(genutil.Address("DE", "JP"), "City:city", "Zip:zip", "Country:country", "PostCode=Zip:post_code"),
+		attr.Group(genutil.Person(), "ContactName=Name:contact_name", "Email:email"),
+	).Table("shippings")
+
+	shippings := shippingFactory.MustBuildN(20).([]*Shipping)
+	for _, s := range shippings {
+		code := cities[s.City]
+		require.Contains(t, []string{"DE", "JP"}, code, s.City)
+		assert.Equal(t, genutil.Locales[code].Country, s.Country)
+		assert.Equal(t, s.Zip, s.PostCode)
+		if code == "JP" {
+			assert.Regexp(t, `^\d{3}-\d{4}$`, s.Zip)
+		} else {
+			assert.Regexp(t, `^\d{5}$`, s.Zip)
+		}
+		first := strings.ToLower(strings.Split(s.ContactName, " ")[0])
+		assert.True(t, strings.HasPrefix(s.Email, first+"."), s.Email)
+	}
+
+	omitted := shippingFactory.Omit("Zip").MustBuild().(*Shipping)
+	assert.Empty(t, omitted.Zip)
+	assert.NotEmpty(t, omitted.City)
+	only := shippingFactory.Only("City").MustBuild().(*Shipping)
+	assert.NotEmpty(t, only.City)
+	assert.Empty(t, only.Country)
+
+	var columnValues map[string]interface{}
+	gofactory.Opt().SetInsertFunc(func(job *dbutil.InsertJob) error {
+		columnValues = job.GetColumnValues()
+		return nil
+	})
+	defer gofactory.Opt().SetInsertFunc(nil)
+	s := shippingFactory.MustInsert().(*Shipping)
+	assert.Equal(t, s.City, columnValues["city"])
+	assert.Equal(t, s.Zip, columnValues["post_code"])
+	assert.Equal(t, s.ContactName, columnValues["contact_name"])
+
+	_, err := gofactory.New(&Shipping{}, attr.Group(genutil.Address(), "City=Town")).Build()
+	assert.Error(t, err)
+
+	countries := func() genutil.Record {
+		return genutil.Record{"Country": genutil.RandStrSet("DE", "JP")()}
+	}
+	uniqueFactory := gofactory.New(&Shipping{}, attr.Unique(attr.Group(countries, "Country"), 50))
+	unique := uniqueFactory.MustBuildN(2).([]*Shipping)
+	assert.NotEqual(t, unique[0].Country, unique[1].Country)
+	_, err = uniqueFactory.Build()
+	assert.True(t, errors.Is(err, genutil.ErrUniqueExhausted), err)
+
+	// the original group is unchanged by Unique and the record is kept per object, so the fields stay correlated when
+	// the group is shared by concurrent builds
+	shared := attr.Group(genutil.Address("DE", "JP"), "City", "Country")
+	attr.Unique(shared, 1)
+	assert.Len(t, gofactory.New(&Shipping{}, shared).MustBuildN(5).([]*Shipping), 5)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, s := range gofactory.New(&Shipping{}, shared).MustBuildN(20).([]*Shipping) {
+				assert.Equal(t, genutil.Locales[cities[s.City]].Country, s.Country, s.City)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type Staff struct {
+	ID    int64
+	First string
+	Last  string
+	Email string
+	SKU   string
+	Plate string
+}
+
+func TestRegexGenerators(t *testing.T) {
+	patterns := []string{
+		`SKU-[A-Z]{3}-\d{4}`,
+		`^[A-Z]{2}-\d{1,4}[A-Z]?$`,
+		`(?i)ab(c|de)+f*`,
+		`[^a-z]\w\s?.x{2,}`,
+		`[a-f0-9]{8}-[a-f0-9]{4}`,
+	}
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+		gen := genutil.Regex(pattern)
+		for i := 0; i < 50; i++ {
+			s := gen()
+			assert.Regexp(t, re, s, pattern)
+		}
+	}
+	assert.Panics(t, func() { genutil.Regex(`[a-`) })
+
+	staff := gofactory.New(
+		&Staff{},
+		attr.Str("SKU", genutil.Regex(`SKU-[A-Z]{3}-\d{4}`)),
+		attr.Unique(attr.Str("Plate", genutil.Regex(`[A-Z]{2}-[A-Z]{1,2} \d{1,4}`))),
+	).MustBuild().(*Staff)
+	assert.Regexp(t, `^SKU-[A-Z]{3}-\d{4}$`, staff.SKU)
+	assert.Regexp(t, `^[A-Z]{2}-[A-Z]{1,2} \d{1,4}$`, staff.Plate)
+}
+
+func TestTemplateGenerators(t *testing.T) {
+	staffFactory := gofactory.New(
+		&Staff{},
+		attr.Int("ID", genutil.SeqInt(1, 1)),
+		attr.Str("First", genutil.FixStr("Ada")),
+		attr.Str("Last", genutil.RandStrSet("Lovelace", "Byron")),
+		attr.StrFnErr("Email", genutil.Template("{{.First | lower}}.{{.Last | lower}}+{{.ID}}@corp.com")),
+	)
+	staffs := staffFactory.MustBuildN(3).([]*Staff)
+	for i, s := range staffs {
+		assert.Equal(t, strings.ToLower(s.First+"."+s.Last)+"+"+strconv.Itoa(i+1)+"@corp.com", s.Email)
+	}
+
+	render := genutil.Template("{{.First}} {{.Last}}")
+	rendered, err := render(map[string]string{"First": "Ada", "Last": "Byron"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ada Byron", rendered)
+	assert.Panics(t, func() { genutil.Template("{{.First") })
+
+	_, err = render(map[string]string{"First": "Ada"})
+	assert.Error(t, err)
+	assert.NotPanics(t, func() {
+		_, err = staffFactory.Attrs(attr.StrFnErr("Email", genutil.Template("{{.Missing}}"))).Build()
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "attribute(Email)")
+}
+
+type Price struct {
+	Amount   decimal.Decimal
+	Discount decimal.NullDecimal
+	Text     string
+	Note     sql.NullString
+	Serial   *big.Int
+	Currency string
+}
+
+func TestDecimalGenerators(t *testing.T) {
+	min, max := decimal.RequireFromString("1.5"), decimal.RequireFromString("99.99")
+	gen := genutil.RandDecimal(1.5, 99.99, 2)
+	for i := 0; i < 100; i++ {
+		d := gen()
+		assert.Equal(t, int32(-2), d.Exponent())
+		assert.True(t, d.GreaterThanOrEqual(min) && d.LessThanOrEqual(max), d.String())
+	}
+	numeric := genutil.RandNumeric(5, 2)
+	for i := 0; i < 100; i++ {
+		d := numeric()
+		assert.True(t, d.LessThan(decimal.NewFromInt(1000)) && !d.IsNegative(), d.String())
+		assert.Regexp(t, `^\d{1,3}\.\d{2}$`, d.StringFixed(2))
+	}
+	assert.Equal(t, "19.90", genutil.FixDecimal("19.90")().StringFixed(2))
+
+	assert.Equal(t, int32(0), genutil.RandAmount(1, 1000, "JPY")().Exponent())
+	assert.Equal(t, int32(-3), genutil.RandAmount(1, 1000, "KWD")().Exponent())
+	assert.Contains(t, genutil.Currencies, genutil.RandCurrency()())
+	assert.Contains(t, []string{"USD", "EUR"}, genutil.RandCurrency("USD", "EUR")())
+
+	lo, _ := new(big.Int).SetString("100000000000000000000", 10)
+	hi, _ := new(big.Int).SetString("100000000000000000010", 10)
+	bigGen := genutil.RandBigInt(lo, hi)
+	for i := 0; i < 20; i++ {
+		n := bigGen()
+		assert.True(t, n.Cmp(lo) >= 0 && n.Cmp(hi) <= 0, n.String())
+	}
+	assert.LessOrEqual(t, len(genutil.RandBigIntDigits(30)().String()), 30)
+
+	p := gofactory.New(
+		&Price{},
+		attr.Group(genutil.Money(1, 100, "USD"), "Amount", "Currency"),
+		attr.Decimal("Discount", genutil.FixDecimal("0.50")),
+		attr.Decimal("Text", genutil.FixDecimal("12.30")),
+		attr.Decimal("Note", genutil.FixDecimal("7.125")),
+		attr.BigInt("Serial", genutil.RandBigInt(lo, hi)),
+	).MustBuild().(*Price)
+	assert.Equal(t, "USD", p.Currency)
+	assert.Equal(t, int32(-2), p.Amount.Exponent())
+	assert.True(t, p.Discount.Valid)
+	assert.Equal(t, "0.5", p.Discount.Decimal.String())
+	assert.Equal(t, "12.3", p.Text)
+	assert.Equal(t, sql.NullString{String: "7.125", Valid: true}, p.Note)
+	assert.True(t, p.Serial.Cmp(lo) >= 0)
+}
@@ -4,8 +4,12 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
 	factory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/dbutil"
+	"github.com/vx416/gogo-factory/genutil"
 )
 
 func TestSqlite(t *testing.T) {
@@ -114,6 +118,61 @@ func (suite *insertSuite) TestInsertOne() {
 	}
 }
 
+func (suite *insertSuite) TestInsertNull() {
+	employee := EmployeeFactory.Attrs(
+		attr.Nullable(attr.Int("Age", genutil.RandInt(18, 60), "age"), 1),
+		attr.Nullable(attr.Time("UpdatedAt", genutil.Now(nil), "updated_at"), 1),
+	).MustInsert().(*Employee)
+	suite.Nil(employee.Age)
+
+	var nulls int
+	err := suite.db.QueryRow("select count(*) from employees where age is null and updated_at is null").Scan(&nulls)
+	suite.Require().NoError(err)
+	suite.Equal(1, nulls)
+	employees, _, err := AllEmployees(suite.db, suite.dbType)
+	suite.Require().NoError(err)
+	suite.Require().Len(employees, 1)
+	employeeEq(suite, employee, employees[0])
+	suite.False(employees[0].UpdatedAt.Valid)
+}
+
+func (suite *insertSuite) TestInsertDecimal() {
+	invoiceFactory := factory.New(
+		&Invoice{},
+		idAttr(),
+		attr.Group(genutil.Money(1, 10000, "USD", "EUR", "JPY"), "Amount:amount", "Currency:currency"),
+		attr.Nullable(attr.Decimal("Discount", genutil.RandDecimal(0, 100, 2), "discount"), 0.5),
+		attr.Decimal("Tax", genutil.RandNumeric(8, 3), "tax"),
+		attr.BigInt("RefNo", genutil.RandBigIntDigits(18), "ref_no"),
+	).Table("invoices")
+	invoices := invoiceFactory.MustInsertN(20).([]*Invoice)
+
+	rows, err := suite.db.Query("select id, amount, currency, discount, tax, ref_no from invoices order by id")
+	suite.Require().NoError(err)
+	defer rows.Close()
+	i := 0
+	for ; rows.Next(); i++ {
+		var (
+			id              int64
+			amount, tax     decimal.Decimal
+			currency, refNo string
+			discount        decimal.NullDecimal
+		)
+		suite.Require().NoError(rows.Scan(&id, &amount, &currency, &discount, &tax, &refNo))
+		invoice := invoices[i]
+		suite.Equal(invoice.ID, id)
+		suite.True(invoice.Amount.Equal(amount), "%s != %s", invoice.Amount, amount)
+		suite.Equal(invoice.Currency, currency)
+		suite.Equal(genutil.Currencies[currency], -invoice.Amount.Exponent())
+		suite.Equal(invoice.Discount.Valid, discount.Valid)
+		suite.True(invoice.Discount.Decimal.Equal(discount.Decimal))
+		suite.True(decimal.RequireFromString(invoice.Tax).Equal(tax), "%s != %s", invoice.Tax, tax)
+		suite.Equal(invoice.RefNo.String(), refNo)
+	}
+	suite.Require().NoError(rows.Err())
+	suite.Equal(len(invoices), i)
+}
+
 func (suite *insertSuite) TestBelongsTo() {
 	spec := SpecialtyFactory.BelongsToDomain(DomainFactory).MustInsert().(*Specialty)
 	suite.Assert().NotZero(spec.ID)
@@ -322,3 +381,91 @@ func (suite *insertSuite) TestAllN() {
 		suite.Assert().Len(employees[i].Projects[0].Tasks, 10)
 	}
 }
+
+func (suite *insertSuite) TestPolymorphic() {
+	post := PostFactory.HasManyComments(CommentFactory, 3).MustInsert().(*Post)
+	photo := PhotoFactory.HasOneComment(CommentFactory).MustInsert().(*Photo)
+	postAss := PostFactory.ToAssociation().ReferField("ID").ForeignField("CommentableID").ForeignKey("commentable_id").
+		Polymorphic("CommentableType", "commentable_type", "posts")
+	comment := CommentFactory.BelongsTo("Post", postAss).MustInsert().(*Comment)
+
+	comments, err := AllComments(suite.db, suite.dbType)
+	suite.Require().NoError(err)
+	suite.Require().Len(comments, 5)
+	commentsMap := make(map[int64]*Comment)
+	for i := range comments {
+		commentsMap[comments[i].ID] = comments[i]
+	}
+
+	for _, c := range post.Comments {
+		suite.Equal("posts", commentsMap[c.ID].CommentableType)
+		suite.Equal(post.ID, commentsMap[c.ID].CommentableID)
+	}
+	suite.Equal("photos", commentsMap[photo.Comment.ID].CommentableType)
+	suite.Equal(photo.ID, commentsMap[photo.Comment.ID].CommentableID)
+	suite.Equal("posts", commentsMap[comment.ID].CommentableType)
+	suite.Equal(comment.Post.ID, commentsMap[comment.ID].CommentableID)
+}
+
+func (suite *insertSuite) TestSelfRef() {
+	inserted := make([]int64, 0, 1)
+	factory.Opt().SetInsertFunc(func(job *dbutil.InsertJob) error {
+		inserted = append(inserted, job.GetData().(*Category).ID)
+		return dbutil.DefaultInsertFunc(job)
+	})
+	defer factory.Opt().SetInsertFunc(nil)
+
+	category := CategoryFactory.WithParents(2).MustInsert().(*Category)
+	root := CategoryFactory.WithChildren(3, 2).MustInsert().(*Category)
+
+	categories, err := AllCategories(suite.db, suite.dbType)
+	suite.Require().NoError(err)
+	suite.Require().Len(categories, 3+1+3+9)
+	suite.Require().Len(inserted, len(categories))
+
+	order := make(map[int64]int)
+	for i, id := range inserted {
+		order[id] = i
+	}
+	for _, c := range categories {
+		if c.ParentID.Valid {
+			suite.Less(order[c.ParentID.Int64], order[c.ID], "parent should be inserted before child")
+		}
+	}
+	suite.Equal(category.Parent.ID, category.ParentID.Int64)
+	suite.Len(root.Children, 3)
+}
+
+func (suite *insertSuite) TestCompositeForeignKeys() {
+	org := OrganizationFactory.HasManyMembers(MemberFactory, 3).MustInsert().(*Organization)
+	member := MemberFactory.BelongsToOrganization(OrganizationFactory).MustInsert().(*Member)
+
+	members, err := AllMembers(suite.db, suite.dbType)
+	suite.Require().NoError(err)
+	suite.Require().Len(members, 4)
+	membersMap := make(map[int64]*Member)
+	for i := range members {
+		membersMap[members[i].ID] = members[i]
+	}
+	for _, m := range org.Members {
+		suite.Equal(org.TenantID, membersMap[m.ID].TenantID)
+		suite.Equal(org.ID, membersMap[m.ID].OrganizationID.Int64)
+	}
+	suite.Equal(member.Organization.TenantID, membersMap[member.ID].TenantID)
+	suite.Equal(member.Organization.ID, membersMap[member.ID].OrganizationID.Int64)
+}
+
+func (suite *insertSuite) TestInferKeys() {
+	prjAss := ProjectFactory.ToAssociation().JoinTable("employees_projects").Infer()
+	specAss := SpecialtyFactory.BelongsTo("Domain", DomainFactory.ToAssociation().Infer()).
+		ToAssociation().ForeignField("OwnerID").Infer()
+	employee := EmployeeFactory.HasOne("Specialty", specAss).ManyToMany("Projects", prjAss, 2).
+		MustInsert().(*Employee)
+	testEmployeesAndProjects(suite, 2, employee)
+
+	specs, err := AllSpecialties(suite.db, suite.dbType)
+	suite.Require().NoError(err)
+	suite.Require().Len(specs, 1)
+	suite.Equal(employee.ID, specs[0].OwnerID.Int64)
+	suite.Equal(employee.Specialty.Domain.ID, specs[0].DomainID.Int64)
+}
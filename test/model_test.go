@@ -2,9 +2,11 @@ package test
 
 import (
 	"database/sql"
+	"math/big"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/shopspring/decimal"
 )
 
 // Home belongs To User
@@ -81,6 +83,7 @@ type Task struct {
 	Name      string    `db:"name" gorm:"column:name"`
 	ProjectID int64     `db:"project_id" gorm:"column:project_id"`
 	Deadline  time.Time `db:"deadline" gorm:"column:deadline"`
+	Project   *Project  `gorm:"-"`
 }
 
 type Specialty struct {
@@ -92,6 +95,64 @@ type Specialty struct {
 }
 
 type Domain struct {
-	ID   int64  `db:"id" gorm:"column:id"`
-	Name string `db:"name" gorm:"column:name"`
+	ID          int64        `db:"id" gorm:"column:id"`
+	Name        string       `db:"name" gorm:"column:name"`
+	Specialties []*Specialty `gorm:"-"`
+}
+
+type Post struct {
+	ID       int64      `db:"id" gorm:"column:id"`
+	Title    string     `db:"title" gorm:"column:title"`
+	Comments []*Comment `gorm:"polymorphic:Commentable;polymorphicValue:posts"`
+}
+
+type Photo struct {
+	ID      int64    `db:"id" gorm:"column:id"`
+	URL     string   `db:"url" gorm:"column:url"`
+	Comment *Comment `gorm:"-"`
+}
+
+type Video struct {
+	ID       int64      `db:"id" gorm:"column:id"`
+	Comments []*Comment `gorm:"polymorphic:Commentable"`
+}
+
+type Comment struct {
+	ID              int64  `db:"id" gorm:"column:id"`
+	Body            string `db:"body" gorm:"column:body"`
+	CommentableType string `db:"commentable_type" gorm:"column:commentable_type"`
+	CommentableID   int64  `db:"commentable_id" gorm:"column:commentable_id"`
+	Post            *Post  `gorm:"-"`
+}
+
+type Category struct {
+	ID       int64         `db:"id" gorm:"column:id"`
+	Name     string        `db:"name" gorm:"column:name"`
+	ParentID sql.NullInt64 `db:"parent_id" gorm:"column:parent_id"`
+	Parent   *Category     `gorm:"-"`
+	Children []*Category   `gorm:"-"`
+}
+
+type Organization struct {
+	TenantID int64     `db:"tenant_id" gorm:"column:tenant_id"`
+	ID       int64     `db:"id" gorm:"column:id"`
+	Name     string    `db:"name" gorm:"column:name"`
+	Members  []*Member `gorm:"foreignKey:TenantID,OrganizationID;references:TenantID,ID"`
+}
+
+type Member struct {
+	ID             int64         `db:"id" gorm:"column:id"`
+	TenantID       int64         `db:"tenant_id" gorm:"column:tenant_id"`
+	OrganizationID sql.NullInt64 `db:"organization_id" gorm:"column:organization_id"`
+	Name           string        `db:"name" gorm:"column:name"`
+	Organization   *Organization `gorm:"-"`
+}
+
+type Invoice struct {
+	ID       int64               `db:"id" gorm:"column:id"`
+	Amount   decimal.Decimal     `db:"amount" gorm:"column:amount"`
+	Currency string              `db:"currency" gorm:"column:currency"`
+	Discount decimal.NullDecimal `db:"discount" gorm:"column:discount"`
+	Tax      string              `db:"tax" gorm:"column:tax"`
+	RefNo    *big.Int            `db:"ref_no" gorm:"column:ref_no"`
 }
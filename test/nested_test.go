@@ -0,0 +1,86 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gofactory "github.com/vx416/gogo-factory"
+	"github.com/vx416/gogo-factory/attr"
+	"github.com/vx416/gogo-factory/dbutil"
+	"github.com/vx416/gogo-factory/genutil"
+)
+
+type Address struct {
+	City   string `json:"city"`
+	Street string `json:"street"`
+}
+
+type Shop struct {
+	ID        int64             `db:"id"`
+	Address   *Address          `db:"-"`
+	Tags      []string          `db:"tags" gorm:"serializer:json"`
+	Metadata  map[string]string `db:"metadata" gorm:"type:jsonb"`
+	Location  Address           `db:"location" gorm:"type:json"`
+	Logo      []byte            `db:"logo"`
+	Branches  []Address         `db:"-"`
+	Warehouse []*Address        `db:"-"`
+}
+
+func TestNestedAttributes(t *testing.T) {
+	addressFactory := gofactory.New(
+		&Address{},
+		attr.Str("City", genutil.RandCity("")),
+		attr.Str("Street", genutil.RandStreetAddress("")),
+	)
+	shopFactory := gofactory.New(
+		&Shop{},
+		attr.Int("ID", genutil.SeqInt(1, 1), "id"),
+		attr.Str("Address.City", genutil.FixStr("Taipei")),
+		attr.SliceOf("Tags", genutil.RandWords(1), 2, 4, "tags"),
+		attr.MapOf("Metadata", genutil.RandAlph(8), genutil.RandAlph(4), 1, 3, "metadata"),
+		attr.Sub("Location", addressFactory, "location"),
+		attr.Bytes("Logo", genutil.FixBytes([]byte("logo")), "logo"),
+		attr.SubN("Branches", addressFactory, 2, 2),
+		attr.SubN("Warehouse", addressFactory.Attrs(attr.Str("City", genutil.FixStr("Kaohsiung"))), 1, 1),
+	).Table("shops")
+
+	shop := shopFactory.MustBuild().(*Shop)
+	require.NotNil(t, shop.Address)
+	assert.Equal(t, "Taipei", shop.Address.City)
+	assert.GreaterOrEqual(t, len(shop.Tags), 2)
+	assert.LessOrEqual(t, len(shop.Tags), 4)
+	assert.GreaterOrEqual(t, len(shop.Metadata), 1)
+	assert.LessOrEqual(t, len(shop.Metadata), 3)
+	assert.NotEmpty(t, shop.Location.City)
+	assert.Len(t, shop.Branches, 2)
+	assert.NotEmpty(t, shop.Branches[1].Street)
+	require.Len(t, shop.Warehouse, 1)
+	assert.Equal(t, "Kaohsiung", shop.Warehouse[0].City)
+
+	var columnValues map[string]interface{}
+	gofactory.Opt().SetInsertFunc(func(job *dbutil.InsertJob) error {
+		columnValues = job.GetColumnValues()
+		return nil
+	})
+	defer gofactory.Opt().SetInsertFunc(nil)
+
+	shop = shopFactory.MustInsert().(*Shop)
+	assert.Contains(t, columnValues["location"], `"city":"`+shop.Location.City+`"`)
+	assert.Contains(t, columnValues["tags"], `"`+shop.Tags[0]+`"`)
+	assert.IsType(t, "", columnValues["metadata"])
+	// the bytes and the columns not tagged as JSON are inserted as they are
+	assert.Equal(t, []byte("logo"), columnValues["logo"])
+	type PlainShop struct {
+		Tags     []string `db:"tags"`
+		Location Address  `db:"location"`
+	}
+	plainFactory := gofactory.New(
+		&PlainShop{},
+		attr.SliceOf("Tags", genutil.RandWords(1), 1, 1, "tags"),
+		attr.Sub("Location", addressFactory, "location"),
+	).Table("shops")
+	plainShop := plainFactory.MustInsert().(*PlainShop)
+	assert.Equal(t, plainShop.Tags, columnValues["tags"])
+	assert.Equal(t, plainShop.Location, columnValues["location"])
+}
@@ -7,6 +7,13 @@ DROP TABLE IF EXISTS `tasks`;
 DROP TABLE IF EXISTS `domains`;
 DROP TABLE IF EXISTS `specialties`;
 DROP TABLE IF EXISTS `employees_projects`;
+DROP TABLE IF EXISTS `posts`;
+DROP TABLE IF EXISTS `photos`;
+DROP TABLE IF EXISTS `comments`;
+DROP TABLE IF EXISTS `categories`;
+DROP TABLE IF EXISTS `organizations`;
+DROP TABLE IF EXISTS `members`;
+DROP TABLE IF EXISTS `invoices`;
 
 
 CREATE TABLE IF NOT EXISTS `users` (
@@ -80,3 +87,51 @@ CREATE TABLE IF NOT EXISTS `specialties` (
     FOREIGN KEY(owner_id) REFERENCES employees(id),
     FOREIGN KEY(domain_id) REFERENCES domains(id)
 );
+
+CREATE TABLE IF NOT EXISTS `posts` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `title` VARCHAR(64) NULL
+);
+
+CREATE TABLE IF NOT EXISTS `photos` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `url` VARCHAR(255) NULL
+);
+
+CREATE TABLE IF NOT EXISTS `comments` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `body` VARCHAR(255) NULL,
+    `commentable_type` VARCHAR(64) NOT NULL,
+    `commentable_id` INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS `categories` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `name` VARCHAR(64) NULL,
+    `parent_id` INTEGER NULL,
+    FOREIGN KEY(parent_id) REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS `organizations` (
+    `tenant_id` INTEGER NOT NULL,
+    `id` INTEGER NOT NULL,
+    `name` VARCHAR(64) NULL,
+    PRIMARY KEY(tenant_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS `members` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `tenant_id` INTEGER NOT NULL,
+    `organization_id` INTEGER NULL,
+    `name` VARCHAR(64) NULL,
+    FOREIGN KEY(tenant_id, organization_id) REFERENCES organizations(tenant_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS `invoices` (
+    `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+    `amount` NUMERIC(12, 2) NOT NULL,
+    `currency` VARCHAR(3) NOT NULL,
+    `discount` NUMERIC(10, 2) NULL,
+    `tax` NUMERIC(8, 3) NOT NULL,
+    `ref_no` NUMERIC(18, 0) NOT NULL
+);
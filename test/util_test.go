@@ -86,9 +86,44 @@ func AllSpecialties(db *sql.DB, driver string) ([]*Specialty, error) {
 	return data, nil
 }
 
+func AllComments(db *sql.DB, driver string) ([]*Comment, error) {
+	xDB := sqlx.NewDb(db, driver)
+	data := make([]*Comment, 0, 1)
+	err := xDB.Select(&data, "select * from comments order by id")
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func AllCategories(db *sql.DB, driver string) ([]*Category, error) {
+	xDB := sqlx.NewDb(db, driver)
+	data := make([]*Category, 0, 1)
+	err := xDB.Select(&data, "select * from categories order by id")
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func AllMembers(db *sql.DB, driver string) ([]*Member, error) {
+	xDB := sqlx.NewDb(db, driver)
+	data := make([]*Member, 0, 1)
+	err := xDB.Select(&data, "select * from members order by id")
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 func Clear(db *sql.DB) error {
 	var err error
-	tables := []string{"employees", "projects", "tasks", "domains", "specialties", "employees_projects"}
+	tables := []string{"employees", "projects", "tasks", "domains", "specialties", "employees_projects",
+		"posts", "photos", "comments", "categories",
+		"organizations", "members", "invoices"}
 	for _, table := range tables {
 		_, err = db.Exec("DELETE FROM " + table)
 	}